@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// githubEnvKey matches the env entries of a workflow, job or step, capturing the job, step index and variable name.
+var githubEnvKey = regexp.MustCompile(`^(?:jobs\.([^.]+)\.(?:steps\.\[(\d+)\]\.)?)?env\.([^.]+)$`)
+
+// gitlabVariablesKey matches the variables of the pipeline, a job or a rule, capturing the prefix, variable name and the
+// field of an expanded {value: ..., description: ...} definition.
+var gitlabVariablesKey = regexp.MustCompile(`^(?:(.+)\.)?variables\.([^.]+)(?:\.([^.]+))?$`)
+
+// ciReference matches a ${{ secrets.X }} or ${{ vars.X }} expression, capturing the context and name.
+var ciReference = regexp.MustCompile(`\$\{\{\s*(secrets|vars)\.([A-Za-z_][A-Za-z0-9_]*)\s*}}`)
+
+// ParseCIFile parses a GitHub Actions workflow or GitLab CI file and returns all matches.
+// Workflow, job and step env entries and GitLab variables are reported by variable name, scoped to where they are defined,
+// and every ${{ secrets.X }} or ${{ vars.X }} expression is reported as secrets.X or vars.X.
+// Everything else is searched as regular YAML.
+// Parses .github/workflows/*.yml, .gitlab-ci.yml
+func ParseCIFile(filePath string, re *regexp.Regexp) ([]Match, error) {
+	entries, err := ParseYAMLFile(filePath, matchAll)
+	if err != nil {
+		return nil, err
+	}
+
+	gitlab := isGitLabCIFile(filePath)
+	names := make(map[string]string)
+	for _, entry := range entries {
+		names[entry.Key] = entry.Value
+	}
+
+	var matches []Match
+	for _, entry := range entries {
+		match := entry
+		switch {
+		case !gitlab && githubEnvKey.MatchString(entry.Key):
+			groups := githubEnvKey.FindStringSubmatch(entry.Key)
+			match.Key, match.Scope = groups[3], githubScope(groups[1], groups[2], names)
+		case gitlab && gitlabVariablesKey.MatchString(entry.Key):
+			groups := gitlabVariablesKey.FindStringSubmatch(entry.Key)
+			if groups[3] != "" && groups[3] != "value" {
+				// e.g. the description or options of a variable
+				continue
+			}
+			match.Key, match.Scope = groups[2], gitlabScope(groups[1])
+		}
+
+		if re.MatchString(match.Key) {
+			matches = append(matches, match)
+		}
+
+		for _, groups := range ciReference.FindAllStringSubmatch(entry.Value, -1) {
+			reference := groups[1] + "." + groups[2]
+			if re.MatchString(reference) {
+				matches = append(matches, Match{
+					Path:    entry.Path,
+					LineNum: entry.LineNum,
+					Column:  entry.Column,
+					Key:     reference,
+					Value:   entry.Value,
+					Scope:   "referenced by " + entry.Key,
+				})
+			}
+		}
+	}
+
+	return matches, nil
+}
+
+// githubScope describes where an env entry is defined, using the name of the step if it has one.
+func githubScope(job, step string, names map[string]string) string {
+	if job == "" {
+		return "workflow"
+	}
+	if step == "" {
+		return "job " + job
+	}
+
+	prefix := fmt.Sprintf("jobs.%s.steps.[%s].", job, step)
+	for _, key := range []string{"name", "id", "uses"} {
+		if name, ok := names[prefix+key]; ok {
+			return fmt.Sprintf("job %s, step %s", job, name)
+		}
+	}
+	return fmt.Sprintf("job %s, step %s", job, step)
+}
+
+// gitlabScope describes where variables are defined, from the key prefix of the variables block.
+func gitlabScope(prefix string) string {
+	job, rule, _ := strings.Cut(prefix, ".rules.")
+	switch {
+	case job == "":
+		return "global"
+	case job == "workflow":
+		return "workflow rules"
+	case rule != "":
+		return fmt.Sprintf("job %s, rules", job)
+	}
+	return "job " + job
+}
+
+// isCIFile reports whether the given path is a GitHub Actions workflow or a GitLab CI file.
+func isCIFile(path string) bool {
+	return isGitHubWorkflowFile(path) || isGitLabCIFile(path)
+}
+
+func isGitHubWorkflowFile(path string) bool {
+	dir := filepath.Dir(path)
+	ext := filepath.Ext(path)
+	return filepath.Base(dir) == "workflows" && filepath.Base(filepath.Dir(dir)) == ".github" && (ext == ".yml" || ext == ".yaml")
+}
+
+func isGitLabCIFile(path string) bool {
+	return strings.HasSuffix(filepath.Base(path), ".gitlab-ci.yml") || strings.HasSuffix(filepath.Base(path), ".gitlab-ci.yaml")
+}
+
+// isCIPath reports whether the given path is CI configuration that is searched even though it is hidden:
+// the .github directory, its workflows directory and the workflows in it, or a GitLab CI file.
+func isCIPath(path string, isDir bool) bool {
+	base := filepath.Base(path)
+	if isDir {
+		return base == ".github" || base == "workflows" && filepath.Base(filepath.Dir(path)) == ".github"
+	}
+	return isGitHubWorkflowFile(path) || isGitLabCIFile(path)
+}
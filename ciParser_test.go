@@ -0,0 +1,37 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestParseCIFile(t *testing.T) {
+	workflow := abs("./testdata/unit/fixtures/ci/.github/workflows/deploy.yml")
+	gitlab := abs("./testdata/unit/fixtures/ci/.gitlab-ci.yml")
+	testCases := []testCaseParseFile{
+		{
+			filePath:      workflow,
+			searchPattern: "deploy_",
+			expectedMatches: []Match{
+				{Path: workflow, LineNum: 4, Column: 3, Key: "DEPLOY_REGION", Value: "eu-west-2", Scope: "workflow"},
+				{Path: workflow, LineNum: 9, Column: 7, Key: "DEPLOY_TARGET", Value: "staging", Scope: "job build"},
+				{Path: workflow, LineNum: 14, Column: 11, Key: "DEPLOY_TOKEN", Value: "${{ secrets.DEPLOY_TOKEN }}", Scope: "job build, step Push image"},
+				{Path: workflow, LineNum: 14, Column: 11, Key: "secrets.DEPLOY_TOKEN", Value: "${{ secrets.DEPLOY_TOKEN }}", Scope: "referenced by jobs.build.steps.[1].env.DEPLOY_TOKEN"},
+				{Path: workflow, LineNum: 15, Column: 11, Key: "DEPLOY_URL", Value: "https://${{ vars.DEPLOY_HOST }}/api", Scope: "job build, step Push image"},
+				{Path: workflow, LineNum: 15, Column: 11, Key: "vars.DEPLOY_HOST", Value: "https://${{ vars.DEPLOY_HOST }}/api", Scope: "referenced by jobs.build.steps.[1].env.DEPLOY_URL"},
+				{Path: workflow, LineNum: 19, Column: 11, Key: "DEPLOY_CHANNEL", Value: "releases", Scope: "job build, step notify"},
+			},
+		},
+		{
+			filePath:      gitlab,
+			searchPattern: "deploy_",
+			expectedMatches: []Match{
+				{Path: gitlab, LineNum: 2, Column: 3, Key: "DEPLOY_REGION", Value: "eu-west-2", Scope: "global"},
+				{Path: gitlab, LineNum: 4, Column: 5, Key: "DEPLOY_MODE", Value: "fast", Scope: "global"},
+				{Path: gitlab, LineNum: 10, Column: 9, Key: "DEPLOY_TARGET", Value: "production", Scope: "workflow rules"},
+				{Path: gitlab, LineNum: 14, Column: 5, Key: "DEPLOY_TARGET", Value: "staging", Scope: "job deploy"},
+			},
+		},
+	}
+
+	assertParsedMatches(t, ParseCIFile, testCases)
+}
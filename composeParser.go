@@ -0,0 +1,142 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// composeEnvironmentKey matches the flattened YAML keys of service environment entries, which are reported by variable name instead.
+var composeEnvironmentKey = regexp.MustCompile(`^services\.[^.]+\.environment\.`)
+
+// ParseComposeFile parses a docker-compose file and returns all matches.
+// Service environment entries (list and map form) are reported by variable name, scoped to their service,
+// and the variables of any env_file a service loads from inside the searched directory are reported against that service.
+// Everything else is searched as regular YAML.
+// Parses docker-compose*.yml, compose*.yml
+func ParseComposeFile(filePath string, re *regexp.Regexp) ([]Match, error) {
+	file, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	documents, err := decodeYAMLDocuments(file)
+	if err != nil || len(documents) == 0 {
+		return nil, err
+	}
+
+	var matches []Match
+	for _, match := range flattenYAMLDocuments(filePath, documents, re) {
+		if !composeEnvironmentKey.MatchString(match.Key) {
+			matches = append(matches, match)
+		}
+	}
+
+	services := yamlMappingValue(yamlRoot(documents[0]), "services")
+	if services == nil || services.Kind != yaml.MappingNode {
+		return matches, nil
+	}
+
+	for _, pair := range yamlMappingPairs(services) {
+		name, service := pair[0].Value, yamlResolveAlias(pair[1])
+		scope := "service " + name
+
+		for _, entry := range composeEnvironment(yamlMappingValue(service, "environment")) {
+			if re.MatchString(entry.Key) {
+				entry.Path = filePath
+				entry.Scope = scope
+				matches = append(matches, entry)
+			}
+		}
+
+		for _, ref := range composeEnvFiles(yamlMappingValue(service, "env_file")) {
+			envPath, ok := resolveReference(filePath, ref.Value)
+			if !ok {
+				verbose("Not following env_file %s of service %s, it is outside the searched directory", envPath, name)
+				continue
+			}
+
+			envMatches, err := ParseEnvFile(envPath, re)
+			if err != nil {
+				verbose("Error reading env_file %s of service %s: %s", envPath, name, err)
+				continue
+			}
+			for _, envMatch := range envMatches {
+				matches = append(matches, Match{
+					Path:    filePath,
+					LineNum: ref.Line,
+					Key:     envMatch.Key,
+					Value:   envMatch.Value,
+					Scope:   fmt.Sprintf("%s, env_file %s:%d", scope, ref.Value, envMatch.LineNum),
+				})
+			}
+		}
+	}
+
+	return matches, nil
+}
+
+// composeEnvironment returns the entries of a service environment in either the list (KEY=value) or map (KEY: value) form.
+// Aliases and merge keys are resolved, so variables shared through an anchor are reported for every service using them.
+func composeEnvironment(node *yaml.Node) []Match {
+	if node == nil {
+		return nil
+	}
+	node = yamlResolveAlias(node)
+
+	var entries []Match
+	switch node.Kind {
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			item = yamlResolveAlias(item)
+			key, value, _ := strings.Cut(item.Value, "=")
+			entries = append(entries, Match{LineNum: item.Line, Column: item.Column, Key: key, Value: value})
+		}
+	case yaml.MappingNode:
+		for _, pair := range yamlMappingPairs(node) {
+			key, value := pair[0], yamlResolveAlias(pair[1])
+			entry := Match{LineNum: key.Line, Column: key.Column, Key: key.Value}
+			if value.Tag != "!!null" {
+				entry.Value = value.Value
+			}
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// composeEnvFiles returns the env_file references of a service, which can be a string, a list of strings or a list of {path: ...} maps.
+func composeEnvFiles(node *yaml.Node) []*yaml.Node {
+	if node == nil {
+		return nil
+	}
+	node = yamlResolveAlias(node)
+
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return []*yaml.Node{node}
+	case yaml.SequenceNode:
+		var refs []*yaml.Node
+		for _, item := range node.Content {
+			if item = yamlResolveAlias(item); item.Kind == yaml.MappingNode {
+				item = yamlMappingValue(item, "path")
+			}
+			if item != nil && item.Kind == yaml.ScalarNode {
+				refs = append(refs, item)
+			}
+		}
+		return refs
+	}
+	return nil
+}
+
+// isComposeFile reports whether the given path is a docker-compose file.
+func isComposeFile(path string) bool {
+	base := filepath.Base(path)
+	ext := filepath.Ext(base)
+	return (ext == ".yml" || ext == ".yaml") && (strings.HasPrefix(base, "docker-compose") || strings.HasPrefix(base, "compose"))
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseComposeFile(t *testing.T) {
+	path := abs("./testdata/unit/fixtures/compose/docker-compose.yml")
+	anchors := abs("./testdata/unit/fixtures/compose/docker-compose.anchors.yml")
+	testCases := []testCaseParseFile{
+		{
+			filePath:      path,
+			searchPattern: "db",
+			expectedMatches: []Match{
+				{Path: path, LineNum: 12, Column: 5, Key: "services.db.image", Value: "postgres:16"},
+				{Path: path, LineNum: 5, Column: 9, Key: "DB_HOST", Value: "db", Scope: "service api"},
+				{Path: path, LineNum: 6, Column: 9, Key: "DB_PASSWORD", Value: "", Scope: "service api"},
+				{Path: path, LineNum: 8, Key: "DB_PORT", Value: "5432", Scope: "service api, env_file api.env:1"},
+				{Path: path, LineNum: 14, Column: 7, Key: "POSTGRES_DB", Value: "app", Scope: "service db"},
+			},
+		},
+		{
+			filePath:      path,
+			searchPattern: "password",
+			expectedMatches: []Match{
+				{Path: path, LineNum: 6, Column: 9, Key: "DB_PASSWORD", Value: "", Scope: "service api"},
+				{Path: path, LineNum: 15, Column: 7, Key: "POSTGRES_PASSWORD", Value: "", Scope: "service db"},
+			},
+		},
+		{
+			filePath:      anchors,
+			searchPattern: "",
+			expectedMatches: []Match{
+				{Path: anchors, LineNum: 2, Column: 3, Key: "x-common-env.LOG_LEVEL", Value: "info"},
+				{Path: anchors, LineNum: 3, Column: 3, Key: "x-common-env.REGION", Value: "eu-west-2"},
+				{Path: anchors, LineNum: 7, Column: 5, Key: "services.api.image", Value: "example/api:latest"},
+				{Path: anchors, LineNum: 13, Column: 5, Key: "services.worker.image", Value: "example/worker:latest"},
+				{Path: anchors, LineNum: 3, Column: 3, Key: "REGION", Value: "eu-west-2", Scope: "service api"},
+				{Path: anchors, LineNum: 10, Column: 7, Key: "API_KEY", Value: "abc", Scope: "service api"},
+				{Path: anchors, LineNum: 11, Column: 7, Key: "LOG_LEVEL", Value: "debug", Scope: "service api"},
+				{Path: anchors, LineNum: 2, Column: 3, Key: "LOG_LEVEL", Value: "info", Scope: "service worker"},
+				{Path: anchors, LineNum: 3, Column: 3, Key: "REGION", Value: "eu-west-2", Scope: "service worker"},
+			},
+		},
+	}
+
+	assertParsedMatches(t, ParseComposeFile, testCases)
+}
+
+func TestParseComposeFileOutsideSearchRoot(t *testing.T) {
+	host := t.TempDir()
+	envPath := filepath.Join(host, "app.env")
+	if err := os.WriteFile(envPath, []byte("HOST_SECRET=leaked\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write env file: %v", err)
+	}
+
+	repo := t.TempDir()
+	composePath := filepath.Join(repo, "docker-compose.yml")
+	if err := os.WriteFile(composePath, []byte("services:\n  api:\n    env_file: "+envPath+"\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write compose file: %v", err)
+	}
+
+	defer func(root string) { searchRoot = root }(searchRoot)
+	searchRoot = repo
+
+	matches, err := ParseComposeFile(composePath, matchAll)
+	if err != nil {
+		t.Fatalf("ParseComposeFile returned an error: %v", err)
+	}
+
+	// Only the reference is reported, the env_file outside the search root is not read
+	expected := Match{Path: composePath, LineNum: 3, Column: 5, Key: "services.api.env_file", Value: envPath}
+	if len(matches) != 1 || matches[0] != expected {
+		t.Fatalf("Expected only %#v, got %v", expected, matches)
+	}
+}
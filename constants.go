@@ -7,4 +7,14 @@ var ignoredDirectories = []string{"vendor", "node_modules", "__pycache__", "buil
 
 // supportedFileTypes lists the file extensions of files that will be searched for the specified patterns.
 // This allows varip to focus on likely candidates for configuration files while skipping over unrelated file types.
-var supportedFileTypes = []string{".env*", "*.json", "*.properties", "*.yml", "*.yaml"}
+var supportedFileTypes = []string{".env*", "*.json", "*.jsonc", "*.json5", "*.jsonl", "*.ndjson", "*.properties", "*.yml", "*.yaml", "*.toml", "*.ini", "*.cfg", "*.conf", "*.hocon", ".gitconfig", "*.xml", "*.config", "*.tf", "*.tfvars", "*.hcl", "Dockerfile", "Dockerfile.*", "*.Dockerfile", "*.dockerfile", "Containerfile", "*.sh", "*.bash", "*.zsh", ".envrc", ".bashrc", ".bash_profile", ".profile", ".zshrc", ".zshenv", "*.service"}
+
+// jsoncFileTypes lists JSON files that commonly contain comments and trailing commas, which are parsed in tolerant JSONC/JSON5 mode.
+var jsoncFileTypes = []string{"*.jsonc", "*.json5", "tsconfig*.json", "jsconfig*.json", "devcontainer.json", ".devcontainer.json", "launch.json", "settings.json", "tasks.json", "extensions.json"}
+
+// editorConfigDirectories lists the hidden editor and dev container directories whose JSONC files are searched
+// without --show-hidden, e.g. .vscode/launch.json.
+var editorConfigDirectories = []string{".vscode", ".devcontainer"}
+
+// shellFileTypes lists the shell scripts and startup files that are parsed for variable assignments.
+var shellFileTypes = []string{"*.sh", "*.bash", "*.zsh", ".envrc", ".bashrc", ".bash_profile", ".profile", ".zshrc", ".zshenv"}
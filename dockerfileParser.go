@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ParseDockerfile parses a Dockerfile and returns all matches for ENV and ARG instructions.
+// Each match is scoped to its instruction and the build stage it belongs to.
+// Parses Dockerfile, *.Dockerfile, Dockerfile.*, Containerfile
+func ParseDockerfile(filePath string, re *regexp.Regexp) ([]Match, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	lineNum := 0
+
+	var matches []Match
+	escape := `\`
+	stage := ""
+	stageIndex := -1
+
+	// instruction holds the logical line being built up from continuation lines, and the line it starts on
+	var instruction strings.Builder
+	startLine := 0
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		lineNum++
+		trimmed := strings.TrimSpace(line)
+
+		if instruction.Len() == 0 {
+			// The escape parser directive must appear before any instruction
+			if stageIndex < 0 && strings.HasPrefix(strings.ToLower(strings.ReplaceAll(trimmed, " ", "")), "#escape=") {
+				escape = strings.TrimSpace(trimmed[strings.Index(trimmed, "=")+1:])
+				continue
+			}
+			startLine = lineNum
+		}
+
+		// Comments and empty lines are ignored, including within continued instructions
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		if strings.HasSuffix(trimmed, escape) {
+			instruction.WriteString(strings.TrimSuffix(trimmed, escape) + " ")
+			continue
+		}
+		instruction.WriteString(trimmed)
+
+		command, args, _ := strings.Cut(strings.TrimSpace(instruction.String()), " ")
+		args = strings.TrimSpace(args)
+		instruction.Reset()
+
+		switch strings.ToUpper(command) {
+		case "FROM":
+			stageIndex++
+			stage = fmt.Sprintf("%d", stageIndex)
+			words := splitShellWords(args, escape)
+			if len(words) >= 3 && strings.EqualFold(words[len(words)-2], "AS") {
+				stage = words[len(words)-1]
+			}
+		case "ENV", "ARG":
+			instructionType := strings.ToUpper(command)
+			scope := instructionType + ", global"
+			if stageIndex >= 0 {
+				scope = fmt.Sprintf("%s, stage %s", instructionType, stage)
+			}
+
+			for _, pair := range parseDockerfilePairs(instructionType, args, escape) {
+				if re.MatchString(pair[0]) {
+					matches = append(matches, Match{Path: filePath, LineNum: startLine, Key: pair[0], Value: pair[1], Scope: scope})
+				}
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return matches, nil
+}
+
+// parseDockerfilePairs returns the key/value pairs of an ENV or ARG instruction.
+// Handles both the KEY=value form (with multiple pairs) and the legacy ENV KEY value form.
+func parseDockerfilePairs(instructionType, args, escape string) [][2]string {
+	words := splitShellWords(args, escape)
+	if len(words) == 0 {
+		return nil
+	}
+
+	if instructionType == "ENV" && !strings.Contains(words[0], "=") {
+		return [][2]string{{words[0], strings.Join(words[1:], " ")}}
+	}
+
+	var pairs [][2]string
+	for _, word := range words {
+		key, value, _ := strings.Cut(word, "=")
+		pairs = append(pairs, [2]string{key, value})
+	}
+	return pairs
+}
+
+// isDockerfile reports whether the given path is a Dockerfile or Containerfile.
+func isDockerfile(path string) bool {
+	base := filepath.Base(path)
+	return base == "Dockerfile" || base == "Containerfile" || strings.HasPrefix(base, "Dockerfile.") || strings.HasSuffix(strings.ToLower(base), ".dockerfile")
+}
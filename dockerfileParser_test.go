@@ -0,0 +1,40 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestParseDockerfile(t *testing.T) {
+	path := abs("./testdata/unit/fixtures/docker/Dockerfile")
+	testCases := []testCaseParseFile{
+		{
+			filePath:      path,
+			searchPattern: "go",
+			expectedMatches: []Match{
+				{Path: path, LineNum: 2, Key: "GO_VERSION", Value: "1.22", Scope: "ARG, global"},
+				{Path: path, LineNum: 6, Key: "CGO_ENABLED", Value: "0", Scope: "ENV, stage builder"},
+				{Path: path, LineNum: 6, Key: "GOOS", Value: "linux", Scope: "ENV, stage builder"},
+				{Path: path, LineNum: 6, Key: "GOFLAGS", Value: "-mod=vendor -trimpath", Scope: "ENV, stage builder"},
+			},
+		},
+		{
+			filePath:      path,
+			searchPattern: "build_tags",
+			expectedMatches: []Match{
+				{Path: path, LineNum: 5, Key: "BUILD_TAGS", Value: "", Scope: "ARG, stage builder"},
+			},
+		},
+		{
+			filePath:      path,
+			searchPattern: "app_",
+			expectedMatches: []Match{
+				{Path: path, LineNum: 13, Key: "APP_HOME", Value: "/opt/app server", Scope: "ENV, stage 1"},
+				{Path: path, LineNum: 14, Key: "APP_GREETING", Value: `hello "world"`, Scope: "ENV, stage 1"},
+				{Path: path, LineNum: 14, Key: "APP_PORT", Value: "8080", Scope: "ENV, stage 1"},
+				{Path: path, LineNum: 15, Key: "APP_LEGACY", Value: "value with tab", Scope: "ENV, stage 1"},
+			},
+		},
+	}
+
+	assertParsedMatches(t, ParseDockerfile, testCases)
+}
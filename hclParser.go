@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+// ParseHCLFile parses an HCL (Terraform) file, flattens it, and finds matches based on the given regexp.
+// Block types and labels make up the key of each attribute (e.g. resource.aws_db_instance.main.username),
+// literal lists and objects are flattened, and any other expression is reported as its source text.
+// Parses .tf, .tfvars, .hcl
+func ParseHCLFile(filePath string, re *regexp.Regexp) ([]Match, error) {
+	file, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, diags := hclsyntax.ParseConfig(file, filePath, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	var entries []Match
+	flattenHCLBody(file, parsed.Body.(*hclsyntax.Body), "", &entries)
+
+	var matches []Match
+	for _, entry := range entries {
+		if re.MatchString(entry.Key) {
+			entry.Path = filePath
+			matches = append(matches, entry)
+		}
+	}
+
+	return matches, nil
+}
+
+// flattenHCLBody adds the attributes of body and its nested blocks to entries, in the order they appear in src.
+func flattenHCLBody(src []byte, body *hclsyntax.Body, prefix string, entries *[]Match) {
+	var nodes []hclsyntax.Node
+	for _, attr := range body.Attributes {
+		nodes = append(nodes, attr)
+	}
+	for _, block := range body.Blocks {
+		nodes = append(nodes, block)
+	}
+	// Attributes are kept in a map, so sort everything back into source order
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Range().Start.Byte < nodes[j].Range().Start.Byte
+	})
+
+	for _, node := range nodes {
+		switch node := node.(type) {
+		case *hclsyntax.Attribute:
+			flattenHCLExpression(src, node.Expr, prefix+node.Name, node.NameRange.Start.Line, entries)
+		case *hclsyntax.Block:
+			path := strings.Join(append([]string{node.Type}, node.Labels...), ".")
+			flattenHCLBody(src, node.Body, prefix+path+".", entries)
+		}
+	}
+}
+
+// flattenHCLExpression adds the value of expr to entries under key. Lists and objects are flattened,
+// other expressions are reported as their literal value if they have one, or their source text otherwise.
+func flattenHCLExpression(src []byte, expr hclsyntax.Expression, key string, line int, entries *[]Match) {
+	switch expr := expr.(type) {
+	case *hclsyntax.TupleConsExpr:
+		for i, item := range expr.Exprs {
+			flattenHCLExpression(src, item, fmt.Sprintf("%s.[%d]", key, i), item.Range().Start.Line, entries)
+		}
+		return
+	case *hclsyntax.ObjectConsExpr:
+		for _, item := range expr.Items {
+			name := hcl.ExprAsKeyword(item.KeyExpr)
+			if key, ok := item.KeyExpr.(*hclsyntax.ObjectConsKeyExpr); ok && name == "" {
+				name = hclValue(src, key.Wrapped)
+			}
+			flattenHCLExpression(src, item.ValueExpr, key+"."+name, item.KeyExpr.Range().Start.Line, entries)
+		}
+		return
+	}
+
+	*entries = append(*entries, Match{LineNum: line, Key: key, Value: hclValue(src, expr)})
+}
+
+// hclValue returns the value of expr as a string. Literals and templates are unquoted, keeping their interpolations
+// (${...}) as written, any other expression is returned as its source text.
+func hclValue(src []byte, expr hclsyntax.Expression) string {
+	switch expr := expr.(type) {
+	case *hclsyntax.LiteralValueExpr:
+		if expr.Val.IsNull() {
+			return "null"
+		}
+		if str, err := convert.Convert(expr.Val, cty.String); err == nil {
+			return str.AsString()
+		}
+	case *hclsyntax.TemplateWrapExpr:
+		return "${" + hclSource(src, expr.Wrapped) + "}"
+	case *hclsyntax.TemplateExpr:
+		var sb strings.Builder
+		for _, part := range expr.Parts {
+			if literal, ok := part.(*hclsyntax.LiteralValueExpr); ok && literal.Val.Type() == cty.String {
+				sb.WriteString(literal.Val.AsString())
+				continue
+			}
+			sb.WriteString("${" + hclSource(src, part) + "}")
+		}
+		// Heredocs end with the newline before their closing marker
+		if strings.HasPrefix(hclSource(src, expr), "<<") {
+			return strings.TrimSuffix(sb.String(), "\n")
+		}
+		return sb.String()
+	}
+
+	return hclSource(src, expr)
+}
+
+// hclSource returns the source text of the given expression.
+func hclSource(src []byte, expr hclsyntax.Expression) string {
+	return string(expr.Range().SliceBytes(src))
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestParseHclFile(t *testing.T) {
+	tf := abs("./testdata/unit/fixtures/unit.tf")
+	tfvars := abs("./testdata/unit/fixtures/unit.tfvars")
+	testCases := []testCaseParseFile{
+		{
+			filePath:      tf,
+			searchPattern: "db_password",
+			expectedMatches: []Match{
+				{Path: tf, LineNum: 3, Key: "variable.db_password.description", Value: `Password for the "main" database`},
+				{Path: tf, LineNum: 4, Key: "variable.db_password.type", Value: "string"},
+				{Path: tf, LineNum: 5, Key: "variable.db_password.default", Value: "${DB_PASSWORD}"},
+				{Path: tf, LineNum: 6, Key: "variable.db_password.sensitive", Value: "true"},
+			},
+		},
+		{
+			filePath:      tf,
+			searchPattern: "locals",
+			expectedMatches: []Match{
+				{Path: tf, LineNum: 10, Key: "locals.region", Value: "eu-west-2"},
+				{Path: tf, LineNum: 12, Key: "locals.tags.Environment", Value: "dev"},
+				{Path: tf, LineNum: 13, Key: "locals.tags.Owner", Value: "var.owner"},
+			},
+		},
+		{
+			filePath:      tf,
+			searchPattern: "aws_db_instance.main",
+			expectedMatches: []Match{
+				{Path: tf, LineNum: 22, Key: "resource.aws_db_instance.main.username", Value: "admin"},
+				{Path: tf, LineNum: 23, Key: "resource.aws_db_instance.main.password", Value: "var.db_password"},
+				{Path: tf, LineNum: 24, Key: "resource.aws_db_instance.main.port", Value: "5432"},
+				{Path: tf, LineNum: 25, Key: "resource.aws_db_instance.main.allocated_storage", Value: "var.large ? 100 : 20"},
+				{Path: tf, LineNum: 26, Key: "resource.aws_db_instance.main.vpc_security_groups.[0]", Value: "sg-123"},
+				{Path: tf, LineNum: 26, Key: "resource.aws_db_instance.main.vpc_security_groups.[1]", Value: "aws_security_group.db.id"},
+				{Path: tf, LineNum: 29, Key: "resource.aws_db_instance.main.parameters.max_connections", Value: "100"},
+				{Path: tf, LineNum: 32, Key: "resource.aws_db_instance.main.lifecycle.ignore_changes.[0]", Value: "password"},
+			},
+		},
+		{
+			filePath:      tf,
+			searchPattern: "provider",
+			expectedMatches: []Match{
+				{Path: tf, LineNum: 18, Key: "provider.aws.region", Value: "local.region"},
+			},
+		},
+		{
+			filePath:      tf,
+			searchPattern: "user_data",
+			expectedMatches: []Match{
+				{Path: tf, LineNum: 36, Key: "resource.aws_instance.web.user_data", Value: "#!/bin/bash\necho \"hello\""},
+			},
+		},
+		{
+			filePath:      tf,
+			searchPattern: "output",
+			expectedMatches: []Match{
+				{Path: tf, LineNum: 43, Key: "output.names.value", Value: "[for s in var.names : upper(s)]"},
+			},
+		},
+		{
+			filePath:      tfvars,
+			searchPattern: "db_",
+			expectedMatches: []Match{
+				{Path: tfvars, LineNum: 1, Key: "db_username", Value: "admin"},
+				{Path: tfvars, LineNum: 2, Key: "db_port", Value: "5432"},
+			},
+		},
+		{
+			filePath:      tfvars,
+			searchPattern: "cidrs",
+			expectedMatches: []Match{
+				{Path: tfvars, LineNum: 4, Key: "allowed_cidrs.[0]", Value: "10.0.0.0/16"},
+				{Path: tfvars, LineNum: 5, Key: "allowed_cidrs.[1]", Value: "10.1.0.0/16"},
+			},
+		},
+	}
+
+	assertParsedMatches(t, ParseHCLFile, testCases)
+}
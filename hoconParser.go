@@ -0,0 +1,336 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// maxHOCONIncludeDepth limits how deep nested includes are followed.
+const maxHOCONIncludeDepth = 10
+
+// hoconIncludeTarget extracts the file of an include statement, e.g. include required(file("database.conf")).
+var hoconIncludeTarget = regexp.MustCompile(`^include\s+(?:required\(\s*)?(?:(file|url|classpath)\(\s*)?"([^"]*)"`)
+
+// ParseHOCONFile parses a HOCON (Typesafe Config) file, flattens it, and finds matches based on the given regexp.
+// Every value is reported by its fully qualified path, substitutions such as ${?ENV_VAR} are kept in the value as written,
+// and the values of included files are reported against the include statement that loads them.
+// Parses application*.conf, reference.conf, .hocon
+func ParseHOCONFile(filePath string, re *regexp.Regexp) ([]Match, error) {
+	entries, err := parseHOCON(filePath, "", map[string]bool{})
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []Match
+	for _, entry := range entries {
+		if re.MatchString(entry.Key) {
+			entry.Path = filePath
+			matches = append(matches, entry)
+		}
+	}
+
+	return matches, nil
+}
+
+// parseHOCON flattens the HOCON file at filePath into entries, with every key prefixed by prefix.
+// visited holds the files currently being parsed, to prevent include cycles.
+func parseHOCON(filePath, prefix string, visited map[string]bool) ([]Match, error) {
+	file, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	visited[filePath] = true
+	defer delete(visited, filePath)
+
+	// Commas separate fields and array elements, so they are skipped as blanks
+	p := &hoconParser{scanner: newScanner("hocon", string(file), " \t\r\n,", "#", "//"), path: filePath, visited: visited}
+	p.skipBlank()
+	if p.consume('{') {
+		err = p.parseObject(prefix, true)
+	} else {
+		err = p.parseObject(prefix, false)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return p.entries, nil
+}
+
+// hoconParser flattens a HOCON document into key/value entries, following its includes.
+// Substitutions are not resolved and duplicate keys are all reported, rather than merged.
+type hoconParser struct {
+	scanner
+
+	path    string
+	visited map[string]bool
+
+	entries []Match
+}
+
+// parseObject parses fields until the end of the file, or the closing brace of the current object.
+func (p *hoconParser) parseObject(prefix string, braced bool) error {
+	for {
+		p.skipBlank()
+		if p.eof() {
+			if braced {
+				return p.errorf("unexpected end of file, expected '}'")
+			}
+			return nil
+		}
+		if p.peek() == '}' {
+			if !braced {
+				return p.errorf("unexpected '}'")
+			}
+			p.pos++
+			return nil
+		}
+
+		if hoconIncludeTarget.MatchString(p.src[p.pos:]) {
+			if err := p.parseInclude(prefix); err != nil {
+				return err
+			}
+			continue
+		}
+
+		line := p.line
+		key, err := p.parseKeyPath()
+		if err != nil {
+			return err
+		}
+		p.skipSpaces()
+
+		switch {
+		case p.consume('{'):
+			err = p.parseObject(prefix+key+".", true)
+		case p.consume('=') || p.consume(':'):
+			p.skipSpaces()
+			err = p.parseValue(prefix+key, line)
+		case strings.HasPrefix(p.src[p.pos:], "+="):
+			p.pos += 2
+			p.skipSpaces()
+			err = p.parseValue(prefix+key, line)
+		default:
+			err = p.errorf("expected '=', ':' or '{' after key %s", prefix+key)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// parseInclude parses an include statement, adding the entries of the included file under the current prefix.
+// Only files inside the searched directory are followed, url() and classpath() includes are skipped.
+func (p *hoconParser) parseInclude(prefix string) error {
+	line := p.line
+	groups := hoconIncludeTarget.FindStringSubmatch(p.src[p.pos:])
+	p.skipLine()
+
+	kind, target := groups[1], groups[2]
+	if kind == "url" || kind == "classpath" {
+		verbose("Skipping %s include %s in %s", kind, target, p.path)
+		return nil
+	}
+
+	includePath, ok := resolveReference(p.path, target)
+	if !ok {
+		verbose("Not following include %s in %s, it is outside the searched directory", target, p.path)
+		return nil
+	}
+	if p.visited[includePath] || len(p.visited) >= maxHOCONIncludeDepth {
+		verbose("Skipping recursive include %s in %s", target, p.path)
+		return nil
+	}
+
+	entries, err := parseHOCON(includePath, prefix, p.visited)
+	if err != nil {
+		verbose("Error reading include %s in %s: %s", target, p.path, err)
+		return nil
+	}
+	for _, entry := range entries {
+		scope := fmt.Sprintf("include %s:%d", target, entry.LineNum)
+		if entry.Scope != "" {
+			scope += ", " + entry.Scope
+		}
+		p.entries = append(p.entries, Match{LineNum: line, Key: entry.Key, Value: entry.Value, Scope: scope})
+	}
+
+	return nil
+}
+
+// parseKeyPath parses a (possibly dotted and quoted) key path, returning it joined with dots.
+func (p *hoconParser) parseKeyPath() (string, error) {
+	var keys []string
+	for {
+		var key string
+		if !p.eof() && p.peek() == '"' {
+			s, err := p.parseQuoted()
+			if err != nil {
+				return "", err
+			}
+			key = s
+		} else {
+			start := p.pos
+			for !p.eof() && isHOCONKeyChar(p.src[p.pos:]) {
+				p.pos++
+			}
+			key = strings.TrimSpace(p.src[start:p.pos])
+			if key == "" {
+				if p.eof() {
+					return "", p.errorf("unexpected end of file, expected key")
+				}
+				return "", p.errorf("invalid key character %q", p.peek())
+			}
+		}
+		keys = append(keys, key)
+
+		if !p.consume('.') {
+			return strings.Join(keys, "."), nil
+		}
+	}
+}
+
+func (p *hoconParser) parseValue(key string, line int) error {
+	if p.eof() {
+		return p.errorf("missing value for key %s", key)
+	}
+
+	switch p.peek() {
+	case '{':
+		// Adjacent objects are merged, e.g. a = { b = 1 } { c = 2 }
+		for p.consume('{') {
+			if err := p.parseObject(key+".", true); err != nil {
+				return err
+			}
+			p.skipSpaces()
+		}
+		return nil
+	case '[':
+		return p.parseArray(key)
+	}
+
+	value, err := p.parseConcatenation()
+	if err != nil {
+		return err
+	}
+	p.entries = append(p.entries, Match{LineNum: line, Key: key, Value: value})
+	return nil
+}
+
+func (p *hoconParser) parseArray(key string) error {
+	p.pos++
+	for i := 0; ; i++ {
+		p.skipBlank()
+		if p.eof() {
+			return p.errorf("unexpected end of file, expected ']' in array %s", key)
+		}
+		if p.consume(']') {
+			return nil
+		}
+
+		if err := p.parseValue(fmt.Sprintf("%s.[%d]", key, i), p.line); err != nil {
+			return err
+		}
+	}
+}
+
+// parseConcatenation parses a simple value up to the end of the field, which may be a concatenation of quoted strings,
+// unquoted text and substitutions. Quoted strings are unquoted, substitutions are kept as written.
+func (p *hoconParser) parseConcatenation() (string, error) {
+	var sb strings.Builder
+	// pending holds unquoted whitespace, which is only kept between two parts of the value
+	pending := ""
+
+	for !p.eof() {
+		rest := p.src[p.pos:]
+		c := rest[0]
+
+		var part string
+		switch {
+		case strings.ContainsRune("\n,}]#", rune(c)) || strings.HasPrefix(rest, "//"):
+			return sb.String(), nil
+		case c == ' ' || c == '\t' || c == '\r':
+			pending += string(c)
+			p.pos++
+			continue
+		case strings.HasPrefix(rest, `"""`):
+			end := strings.Index(rest[3:], `"""`)
+			if end < 0 {
+				return "", p.errorf("unterminated multi-line string")
+			}
+			// Quotes directly before the closing delimiter are part of the string
+			for 3+end+3 < len(rest) && rest[3+end+3] == '"' {
+				end++
+			}
+			part = rest[3 : 3+end]
+			p.line += strings.Count(part, "\n")
+			p.pos += 3 + end + 3
+		case c == '"':
+			s, err := p.parseQuoted()
+			if err != nil {
+				return "", err
+			}
+			part = s
+		case strings.HasPrefix(rest, "${"):
+			end := strings.IndexByte(rest, '}')
+			if end < 0 {
+				return "", p.errorf("unterminated substitution")
+			}
+			part = rest[:end+1]
+			p.pos += end + 1
+		default:
+			part = string(c)
+			p.pos++
+		}
+
+		if sb.Len() > 0 {
+			sb.WriteString(pending)
+		}
+		pending = ""
+		sb.WriteString(part)
+	}
+
+	return sb.String(), nil
+}
+
+// parseQuoted parses a JSON style double quoted string.
+func (p *hoconParser) parseQuoted() (string, error) {
+	end := p.pos + 1
+	for ; end < len(p.src) && p.src[end] != '"'; end++ {
+		if p.src[end] == '\n' {
+			return "", p.errorf("unterminated string")
+		}
+		if p.src[end] == '\\' {
+			end++
+		}
+	}
+	if end >= len(p.src) {
+		return "", p.errorf("unterminated string")
+	}
+
+	s, err := strconv.Unquote(p.src[p.pos : end+1])
+	if err != nil {
+		return "", p.errorf("invalid string %s", p.src[p.pos:end+1])
+	}
+	p.pos = end + 1
+	return s, nil
+}
+
+// isHOCONKeyChar reports whether s starts with a character that can be part of an unquoted key.
+func isHOCONKeyChar(s string) bool {
+	if strings.HasPrefix(s, "+=") || strings.HasPrefix(s, "//") {
+		return false
+	}
+	return !strings.ContainsRune(".=:{}[],#\"$\n\r", rune(s[0]))
+}
+
+// isHOCONFile reports whether the given path is a HOCON file rather than an INI style .conf file.
+func isHOCONFile(path string) bool {
+	base := filepath.Base(path)
+	return strings.HasSuffix(base, ".hocon") || base == "reference.conf" || strings.HasPrefix(base, "application") && strings.HasSuffix(base, ".conf")
+}
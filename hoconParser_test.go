@@ -0,0 +1,45 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestParseHoconFile(t *testing.T) {
+	path := abs("./testdata/unit/fixtures/hocon/application.conf")
+	testCases := []testCaseParseFile{
+		{
+			filePath:      path,
+			searchPattern: "app.",
+			expectedMatches: []Match{
+				{Path: path, LineNum: 6, Key: "app.name", Value: "varip"},
+				{Path: path, LineNum: 7, Key: "app.http.port", Value: "9000"},
+				{Path: path, LineNum: 8, Key: "app.http.port", Value: "${?HTTP_PORT}"},
+				{Path: path, LineNum: 9, Key: "app.secret", Value: "${?APP_SECRET}"},
+				{Path: path, LineNum: 11, Key: "app.hosts.[0]", Value: "alpha.example.com"},
+				{Path: path, LineNum: 12, Key: "app.hosts.[1]", Value: "beta.example.com"},
+				{Path: path, LineNum: 12, Key: "app.hosts.[2]", Value: "gamma"},
+				{Path: path, LineNum: 14, Key: "app.url", Value: "http://${app.host}:8080"},
+				{Path: path, LineNum: 15, Key: "app.description", Value: "multi\nline"},
+			},
+		},
+		{
+			filePath:      path,
+			searchPattern: "akka",
+			expectedMatches: []Match{
+				{Path: path, LineNum: 19, Key: "akka.loglevel", Value: "DEBUG"},
+				{Path: path, LineNum: 20, Key: "akka.actor.provider", Value: "cluster"},
+				{Path: path, LineNum: 20, Key: "akka.actor.quoted.key", Value: "yes"},
+			},
+		},
+		{
+			filePath:      path,
+			searchPattern: "db.default",
+			expectedMatches: []Match{
+				{Path: path, LineNum: 2, Key: "db.default.url", Value: "jdbc:postgresql://localhost/app", Scope: "include database.conf:2"},
+				{Path: path, LineNum: 2, Key: "db.default.password", Value: "${?DB_PASSWORD}", Scope: "include database.conf:3"},
+			},
+		},
+	}
+
+	assertParsedMatches(t, ParseHOCONFile, testCases)
+}
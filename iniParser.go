@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// ParseINIFile parses an INI style file and returns all matches.
+// Keys are qualified with their section (section.key) and git style subsections are joined with a dot.
+// Lines indented deeper than their key and trailing backslashes continue the previous value.
+// Parses .ini, .cfg, .conf, .gitconfig
+func ParseINIFile(filePath string, re *regexp.Regexp) ([]Match, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	lineNum := 0
+
+	var entries []Match
+	section := ""
+	// current is the index of the entry that continuation lines are appended to, -1 if there is none
+	current := -1
+	currentIndent := 0
+	continued := false
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		lineNum++
+		trimmed := strings.TrimSpace(line)
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+
+		// A trailing backslash or a line indented deeper than its key continues the previous value
+		if current >= 0 && trimmed != "" && !isINIComment(trimmed) && (continued || indent > currentIndent) {
+			var part string
+			part, continued = trimINIContinuation(trimmed)
+			entries[current].Value = strings.TrimSpace(entries[current].Value + " " + unquoteINIValue(stripINIComment(part)))
+			continue
+		}
+		continued = false
+
+		if trimmed == "" || isINIComment(trimmed) {
+			current = -1
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "[") {
+			end := strings.Index(trimmed, "]")
+			if end < 0 {
+				current = -1
+				continue
+			}
+			section = parseINISection(trimmed[1:end])
+			current = -1
+			continue
+		}
+
+		sep := strings.IndexAny(trimmed, "=:")
+		if sep < 0 {
+			current = -1
+			continue
+		}
+
+		key := strings.TrimSpace(trimmed[:sep])
+		if section != "" {
+			key = section + "." + key
+		}
+
+		value, cont := trimINIContinuation(strings.TrimSpace(trimmed[sep+1:]))
+		entries = append(entries, Match{Path: filePath, LineNum: lineNum, Key: key, Value: unquoteINIValue(stripINIComment(value))})
+		current = len(entries) - 1
+		currentIndent = indent
+		continued = cont
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	var matches []Match
+	for _, entry := range entries {
+		if re.MatchString(entry.Key) {
+			matches = append(matches, entry)
+		}
+	}
+
+	return matches, nil
+}
+
+// parseINISection converts a section header into its key prefix, e.g. `remote "origin"` becomes remote.origin
+func parseINISection(header string) string {
+	header = strings.TrimSpace(header)
+	if name, sub, found := strings.Cut(header, " "); found {
+		return name + "." + strings.Trim(strings.TrimSpace(sub), `"`)
+	}
+	return header
+}
+
+func isINIComment(line string) bool {
+	return strings.HasPrefix(line, ";") || strings.HasPrefix(line, "#")
+}
+
+// stripINIComment removes a trailing ; or # comment, if it is preceded by whitespace and not quoted.
+func stripINIComment(value string) string {
+	var quote rune
+	for i, c := range value {
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case (c == ';' || c == '#') && i > 0 && (value[i-1] == ' ' || value[i-1] == '\t'):
+			return strings.TrimSpace(value[:i])
+		}
+	}
+	return value
+}
+
+// trimINIContinuation removes a trailing line continuation backslash and reports whether it was present.
+func trimINIContinuation(value string) (string, bool) {
+	if strings.HasSuffix(value, "\\") {
+		return strings.TrimSpace(strings.TrimSuffix(value, "\\")), true
+	}
+	return value, false
+}
+
+func unquoteINIValue(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
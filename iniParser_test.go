@@ -0,0 +1,46 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestParseIniFile(t *testing.T) {
+	path := abs("./testdata/unit/fixtures/unit.ini")
+	testCases := []testCaseParseFile{
+		{
+			filePath:      path,
+			searchPattern: "database",
+			expectedMatches: []Match{
+				{Path: path, LineNum: 5, Key: "database.host", Value: "db.example.com"},
+				{Path: path, LineNum: 6, Key: "database.port", Value: "5432"},
+				{Path: path, LineNum: 8, Key: "database.password", Value: "${DB_PASSWORD}"},
+				{Path: path, LineNum: 22, Key: "database.timeout", Value: "30"},
+			},
+		},
+		{
+			filePath:      path,
+			searchPattern: "options",
+			expectedMatches: []Match{
+				{Path: path, LineNum: 11, Key: "options.install_requires", Value: "requests click"},
+				{Path: path, LineNum: 14, Key: "options.command", Value: "run --verbose"},
+			},
+		},
+		{
+			filePath:      path,
+			searchPattern: "origin",
+			expectedMatches: []Match{
+				{Path: path, LineNum: 18, Key: "remote.origin.url", Value: "https://github.com/jwtly10/varip#readme"},
+				{Path: path, LineNum: 19, Key: "remote.origin.fetch", Value: "+refs/heads/*:refs/remotes/origin/*"},
+			},
+		},
+		{
+			filePath:      path,
+			searchPattern: "app_name",
+			expectedMatches: []Match{
+				{Path: path, LineNum: 2, Key: "app_name", Value: "varip"},
+			},
+		},
+	}
+
+	assertParsedMatches(t, ParseINIFile, testCases)
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// isJSONCFile reports whether the given path is a JSON file that is expected to contain comments or JSON5 syntax.
+func isJSONCFile(path string) bool {
+	for _, fileType := range jsoncFileTypes {
+		if match, _ := filepath.Match(fileType, filepath.Base(path)); match {
+			return true
+		}
+	}
+	return false
+}
+
+// isEditorConfigPath reports whether the given path is editor configuration that is searched even though it is hidden:
+// one of the editorConfigDirectories, or a JSONC file directly in one of them.
+func isEditorConfigPath(path string, isDir bool) bool {
+	dir := filepath.Base(filepath.Dir(path))
+	if isDir {
+		dir = filepath.Base(path)
+	}
+	for _, editorDir := range editorConfigDirectories {
+		if dir == editorDir {
+			return isDir || isJSONCFile(path)
+		}
+	}
+	return false
+}
+
+// normalizeJSONC converts a JSONC/JSON5 document into strict JSON.
+// Comments and trailing commas are removed, single quoted strings and unquoted keys are double quoted,
+// and JSON5 numbers (hex, leading or trailing decimal points, explicit plus signs, Infinity, NaN) are converted.
+// Returns the converted document, along with the offset in src of each byte of the converted document.
+func normalizeJSONC(src []byte) ([]byte, []int, error) {
+	out := make([]byte, 0, len(src))
+	offsets := make([]int, 0, len(src))
+	emit := func(at int, s string) {
+		out = append(out, s...)
+		for range s {
+			offsets = append(offsets, at)
+		}
+	}
+
+	line := 1
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == '\n':
+			line++
+			emit(i, "\n")
+			i++
+		case c == '/' && i+1 < len(src) && (src[i+1] == '/' || src[i+1] == '*'):
+			end := skipJSONCComment(src, i)
+			if end < 0 {
+				return nil, nil, fmt.Errorf("jsonc: line %d: unterminated comment", line)
+			}
+			line += strings.Count(string(src[i:end]), "\n")
+			i = end
+		case c == ',':
+			// Trailing commas are dropped
+			if next := skipJSONCBlank(src, i+1); next >= len(src) || src[next] != '}' && src[next] != ']' {
+				emit(i, ",")
+			}
+			i++
+		case c == '"' || c == '\'':
+			end, s, err := convertJSONCString(src, i)
+			if err != nil {
+				return nil, nil, fmt.Errorf("jsonc: line %d: %s", line, err)
+			}
+			line += strings.Count(string(src[i:end]), "\n")
+			emit(i, s)
+			i = end
+		case c == '+' || c == '-' || c == '.' || c >= '0' && c <= '9':
+			end := i + 1
+			for end < len(src) && isJSONCWordChar(src[end]) || end < len(src) && (src[end] == '+' || src[end] == '-') && (src[end-1] == 'e' || src[end-1] == 'E') {
+				end++
+			}
+			s, err := convertJSONCNumber(string(src[i:end]))
+			if err != nil {
+				return nil, nil, fmt.Errorf("jsonc: line %d: %s", line, err)
+			}
+			emit(i, s)
+			i = end
+		case isJSONCWordChar(c):
+			end := i + 1
+			for end < len(src) && isJSONCWordChar(src[end]) {
+				end++
+			}
+			word := string(src[i:end])
+			switch word {
+			case "true", "false", "null":
+				emit(i, word)
+			default:
+				// Unquoted keys (and Infinity/NaN values) become strings
+				emit(i, strconv.Quote(word))
+			}
+			i = end
+		default:
+			emit(i, string(c))
+			i++
+		}
+	}
+
+	return out, offsets, nil
+}
+
+// skipJSONCComment returns the offset after the comment starting at i, or -1 if a block comment is not terminated.
+// Line comments end before the newline.
+func skipJSONCComment(src []byte, i int) int {
+	if src[i+1] == '/' {
+		end := strings.IndexByte(string(src[i:]), '\n')
+		if end < 0 {
+			return len(src)
+		}
+		return i + end
+	}
+
+	end := strings.Index(string(src[i+2:]), "*/")
+	if end < 0 {
+		return -1
+	}
+	return i + 2 + end + 2
+}
+
+// skipJSONCBlank returns the offset of the next character that is not whitespace or part of a comment.
+func skipJSONCBlank(src []byte, i int) int {
+	for i < len(src) {
+		switch {
+		case strings.IndexByte(" \t\r\n", src[i]) >= 0:
+			i++
+		case src[i] == '/' && i+1 < len(src) && (src[i+1] == '/' || src[i+1] == '*'):
+			if i = skipJSONCComment(src, i); i < 0 {
+				return len(src)
+			}
+		default:
+			return i
+		}
+	}
+	return i
+}
+
+// convertJSONCString converts the single or double quoted string starting at i into a double quoted JSON string.
+// Returns the offset after the closing quote and the converted string.
+func convertJSONCString(src []byte, i int) (int, string, error) {
+	quote := src[i]
+	var sb strings.Builder
+	sb.WriteByte('"')
+
+	for j := i + 1; j < len(src); j++ {
+		c := src[j]
+		switch {
+		case c == quote:
+			sb.WriteByte('"')
+			return j + 1, sb.String(), nil
+		case c == '\n':
+			return 0, "", fmt.Errorf("unterminated string")
+		case c == '"':
+			sb.WriteString(`\"`)
+		case c == '\\' && j+1 < len(src):
+			j++
+			switch next := src[j]; next {
+			case '\n':
+				// Escaped newlines continue the string on the next line
+			case '\r':
+				if j+1 < len(src) && src[j+1] == '\n' {
+					j++
+				}
+			case '\'':
+				sb.WriteByte('\'')
+			case 'v':
+				sb.WriteString(`\u000b`)
+			case '0':
+				sb.WriteString(`\u0000`)
+			case 'x':
+				if j+2 >= len(src) {
+					return 0, "", fmt.Errorf("invalid escape sequence")
+				}
+				sb.WriteString(`\u00` + string(src[j+1:j+3]))
+				j += 2
+			default:
+				sb.WriteByte('\\')
+				sb.WriteByte(next)
+			}
+		default:
+			sb.WriteByte(c)
+		}
+	}
+
+	return 0, "", fmt.Errorf("unterminated string")
+}
+
+// convertJSONCNumber converts a JSON5 number into a JSON number, or a string for Infinity and NaN.
+func convertJSONCNumber(number string) (string, error) {
+	sign := ""
+	if number[0] == '+' || number[0] == '-' {
+		if number[0] == '-' {
+			sign = "-"
+		}
+		number = number[1:]
+	}
+
+	switch {
+	case number == "Infinity" || number == "NaN":
+		return strconv.Quote(sign + number), nil
+	case strings.HasPrefix(number, "0x") || strings.HasPrefix(number, "0X"):
+		value, err := strconv.ParseUint(number[2:], 16, 64)
+		if err != nil {
+			return "", fmt.Errorf("invalid hexadecimal number %s", number)
+		}
+		return sign + strconv.FormatUint(value, 10), nil
+	}
+
+	if strings.HasPrefix(number, ".") {
+		number = "0" + number
+	}
+	if mantissa, exponent, found := strings.Cut(strings.ToLower(number), "e"); strings.HasSuffix(mantissa, ".") {
+		number = mantissa + "0"
+		if found {
+			number += "e" + exponent
+		}
+	}
+	return sign + number, nil
+}
+
+func isJSONCWordChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '$' || c == '.'
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestParseJsoncFile(t *testing.T) {
+	devcontainer := abs("./testdata/unit/fixtures/jsonc/devcontainer.json")
+	json5 := abs("./testdata/unit/fixtures/jsonc/unit.json5")
+	appsettings := abs("./testdata/unit/fixtures/jsonc/appsettings.json")
+	testCases := []testCaseParseFile{
+		{
+			filePath:      devcontainer,
+			searchPattern: "containerEnv",
+			expectedMatches: []Match{
+				{Path: devcontainer, LineNum: 8, Column: 5, Key: "containerEnv.DB_HOST", Value: "localhost"},
+				{Path: devcontainer, LineNum: 9, Column: 5, Key: "containerEnv.DB_PORT", Value: "5432"},
+			},
+		},
+		{
+			filePath:      devcontainer,
+			searchPattern: "forwardPorts",
+			expectedMatches: []Match{
+				{Path: devcontainer, LineNum: 11, Column: 20, Key: "forwardPorts.[0]", Value: "8080"},
+				{Path: devcontainer, LineNum: 11, Column: 26, Key: "forwardPorts.[1]", Value: "5432"},
+			},
+		},
+		{
+			filePath:      json5,
+			searchPattern: "env.api",
+			expectedMatches: []Match{
+				{Path: json5, LineNum: 4, Column: 5, Key: "env.API_URL", Value: "https://api.example.com"},
+				{Path: json5, LineNum: 5, Column: 5, Key: "env.API_TOKEN", Value: `it's "secret"`},
+				{Path: json5, LineNum: 6, Column: 5, Key: "env.API_RETRIES", Value: "3"},
+				{Path: json5, LineNum: 7, Column: 5, Key: "env.API_MASK", Value: "255"},
+				{Path: json5, LineNum: 8, Column: 5, Key: "env.API_RATIO", Value: "0.5"},
+				{Path: json5, LineNum: 9, Column: 5, Key: "env.API_LIMIT", Value: "Infinity"},
+			},
+		},
+		{
+			filePath:      appsettings,
+			searchPattern: "default",
+			expectedMatches: []Match{
+				{Path: appsettings, LineNum: 4, Column: 5, Key: "ConnectionStrings.Default", Value: "Server=db;Database=app"},
+			},
+		},
+	}
+
+	assertParsedMatches(t, ParseJSONFile, testCases)
+}
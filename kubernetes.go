@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// kubernetesDataFields lists the data fields of Secret and ConfigMap resources, and whether their values are base64 encoded.
+var kubernetesDataFields = map[string]map[string]bool{
+	"Secret":    {"data": true, "stringData": false},
+	"ConfigMap": {"data": false, "binaryData": true},
+}
+
+// flattenKubernetesResource flattens a Kubernetes Secret or ConfigMap, reporting its data entries as Kind/name:key.
+// The base64 encoded values are decoded if decodeSecrets is enabled.
+// Returns false if the document is not a Secret or ConfigMap, in which case nothing is flattened.
+func flattenKubernetesResource(document *yaml.Node, flattened *[]Match) bool {
+	resource := yamlRoot(document)
+
+	kind := yamlMappingValue(resource, "kind")
+	if kind == nil {
+		return false
+	}
+	fields, ok := kubernetesDataFields[kind.Value]
+	if !ok {
+		return false
+	}
+
+	name := ""
+	if n := yamlMappingValue(yamlMappingValue(resource, "metadata"), "name"); n != nil {
+		name = n.Value
+	}
+
+	for i := 0; i+1 < len(resource.Content); i += 2 {
+		k, v := resource.Content[i], resource.Content[i+1]
+		encoded, isDataField := fields[k.Value]
+		if !isDataField {
+			flattenYAML(k.Value+".", v, k, flattened)
+			continue
+		}
+
+		if v.Kind != yaml.MappingNode {
+			continue
+		}
+		for j := 0; j+1 < len(v.Content); j += 2 {
+			key, value := v.Content[j], v.Content[j+1]
+			*flattened = append(*flattened, Match{
+				LineNum: key.Line,
+				Column:  key.Column,
+				Key:     fmt.Sprintf("%s/%s:%s", kind.Value, name, key.Value),
+				Value:   kubernetesDataValue(value.Value, encoded),
+			})
+		}
+	}
+
+	return true
+}
+
+// kubernetesDataValue returns the value as is, or decoded if it is base64 encoded and decodeSecrets is enabled.
+// Values that fail to decode are returned as is.
+func kubernetesDataValue(value string, encoded bool) string {
+	if !encoded || !decodeSecrets {
+		return value
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		verbose("Error decoding base64 value: %s", err)
+		return value
+	}
+	return string(decoded)
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestParseKubernetesResources(t *testing.T) {
+	secret := abs("./testdata/unit/fixtures/kubernetes/secret.yaml")
+	configMap := abs("./testdata/unit/fixtures/kubernetes/configmap.yaml")
+	testCases := []struct {
+		testCaseParseFile
+		decode bool
+	}{
+		{
+			testCaseParseFile: testCaseParseFile{
+				filePath:      secret,
+				searchPattern: "db-creds:",
+				expectedMatches: []Match{
+					{Path: secret, LineNum: 7, Column: 3, Key: "Secret/db-creds:password", Value: "czNjcjN0"},
+					{Path: secret, LineNum: 8, Column: 3, Key: "Secret/db-creds:username", Value: "YWRtaW4="},
+					{Path: secret, LineNum: 10, Column: 3, Key: "Secret/db-creds:host", Value: "db.internal"},
+				},
+			},
+		},
+		{
+			testCaseParseFile: testCaseParseFile{
+				filePath:      secret,
+				searchPattern: "db-creds:",
+				expectedMatches: []Match{
+					{Path: secret, LineNum: 7, Column: 3, Key: "Secret/db-creds:password", Value: "s3cr3t"},
+					{Path: secret, LineNum: 8, Column: 3, Key: "Secret/db-creds:username", Value: "admin"},
+					{Path: secret, LineNum: 10, Column: 3, Key: "Secret/db-creds:host", Value: "db.internal"},
+				},
+			},
+			decode: true,
+		},
+		{
+			testCaseParseFile: testCaseParseFile{
+				filePath:      configMap,
+				searchPattern: "app-config",
+				expectedMatches: []Match{
+					{Path: configMap, LineNum: 6, Column: 3, Key: "ConfigMap/app-config:DB_HOST", Value: "db.internal"},
+					{Path: configMap, LineNum: 7, Column: 3, Key: "ConfigMap/app-config:LOG_LEVEL", Value: "debug"},
+				},
+			},
+			decode: true,
+		},
+	}
+
+	defer func() { decodeSecrets = false }()
+
+	for _, testCase := range testCases {
+		decodeSecrets = testCase.decode
+
+		re, err := compilePattern(testCase.searchPattern, patternOptions{ignoreCase: true})
+		if err != nil {
+			t.Fatalf("Failed to compile regex pattern: %v", err)
+		}
+
+		matches, err := ParseYAMLFile(testCase.filePath, re)
+		if err != nil {
+			t.Fatalf("ParseYAMLFile returned an error: %v", err)
+		}
+
+		if len(matches) != len(testCase.expectedMatches) {
+			t.Fatalf("Expected %d matches, got %d: %v", len(testCase.expectedMatches), len(matches), matches)
+		}
+
+		for _, match := range matches {
+			if !contains(testCase.expectedMatches, match) {
+				t.Errorf("Expected match %#v in expected matches: %v", match, testCase.expectedMatches)
+			}
+		}
+	}
+}
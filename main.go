@@ -1,11 +1,13 @@
 package main
 
 import (
+	"errors"
 	"fmt"
-	"log"
 	"os"
 	"path/filepath"
 	"regexp"
+	"regexp/syntax"
+	"unicode"
 
 	"github.com/fatih/color"
 	"github.com/urfave/cli/v2"
@@ -14,6 +16,12 @@ import (
 var verboseEnabled bool = false
 var showColor bool = true
 var showErrors bool = false
+var decodeSecrets bool = false
+var scanUsages bool = false
+
+// searchRoot is the directory being searched. Files referenced by the searched files (e.g. an EnvironmentFile=)
+// are only followed if they are inside it, see resolveReference.
+var searchRoot string = ""
 
 var yellow = color.New(color.FgYellow)
 var blue = color.New(color.FgBlue)
@@ -31,14 +39,16 @@ func main() {
 }
 
 type FileSearcher interface {
-	Search(path string, pattern *regexp.Regexp, showHidden bool) error
+	Search(path string, matcher *Matcher, showHidden bool) error
 }
 
 func setupApp(searchHandler FileSearcher) *cli.App {
 	app := &cli.App{
 		Name:      "varip",
 		Usage:     "Searches for environment variables in files. Searches in the current directory by default.",
-		UsageText: "varip [options] [pattern] [path]",
+		UsageText: "varip [options] [pattern] [path]\nvarip [options] -e pattern [-e pattern...] [--not pattern...] [path]",
+		// Patterns can contain commas, so repeated flags are not split on them
+		DisableSliceFlagSeparator: true,
 		Flags: []cli.Flag{
 			&cli.BoolFlag{
 				Name:  "verbose",
@@ -56,40 +66,142 @@ func setupApp(searchHandler FileSearcher) *cli.App {
 				Name:  "show-hidden",
 				Usage: "Show hidden files and directories",
 			},
+			&cli.BoolFlag{
+				Name:  "decode-secrets",
+				Usage: "Decode the base64 values of Kubernetes Secret data, so they can be searched and shown",
+			},
+			&cli.BoolFlag{
+				Name:  "regex",
+				Usage: "Treat the pattern as a regular expression, by default it is matched as a fixed string",
+			},
+			&cli.BoolFlag{
+				Name:    "case-sensitive",
+				Aliases: []string{"s"},
+				Usage:   "Match case sensitively, by default matching is only case sensitive if the pattern has an uppercase character",
+			},
+			&cli.BoolFlag{
+				Name:    "ignore-case",
+				Aliases: []string{"i"},
+				Usage:   "Match case insensitively, even if the pattern has an uppercase character",
+			},
+			&cli.BoolFlag{
+				Name:    "whole-segment",
+				Aliases: []string{"w"},
+				Usage:   "Only match whole segments of a key (or value), separated by '.', '_', '-', ':', '/' or brackets (e.g. url matches spring.datasource.url, not curlTimeout)",
+			},
+			&cli.BoolFlag{
+				Name:    "exact",
+				Aliases: []string{"x"},
+				Usage:   "Only match the full key (or value)",
+			},
+			&cli.BoolFlag{
+				Name:  "relaxed",
+				Usage: "Match keys across naming conventions, following Spring Boot relaxed binding (e.g. SPRING_DATASOURCE_URL matches spring.datasource-url)",
+			},
+			&cli.BoolFlag{
+				Name:  "value",
+				Usage: "Match the pattern against values instead of keys",
+			},
+			&cli.BoolFlag{
+				Name:  "pair",
+				Usage: "Match a KEY=VALUE pattern, split at the first '=', the key against keys and the value against values",
+			},
+			&cli.StringSliceFlag{
+				Name:    "pattern",
+				Aliases: []string{"e"},
+				Usage:   "Search for this pattern, can be repeated. All arguments are then treated as the path",
+			},
+			&cli.StringFlag{
+				Name:    "file",
+				Aliases: []string{"f"},
+				Usage:   "Search for the patterns in this file, one per line",
+			},
+			&cli.StringSliceFlag{
+				Name:  "not",
+				Usage: "Exclude entries matching this pattern, can be repeated",
+			},
+			&cli.BoolFlag{
+				Name:  "all",
+				Usage: "Only match entries that match every pattern, by default an entry matches if any pattern does",
+			},
+			&cli.BoolFlag{
+				Name:  "usages",
+				Usage: "Also search source code (Go, Java/Kotlin, JS/TS, Python, Ruby) for where environment variables are read",
+			},
 		},
 		Action: func(c *cli.Context) error {
 			verboseEnabled = c.Bool("debug")
 			showErrors = c.Bool("errors")
 			showHidden := c.Bool("show-hidden")
 			showColor = !c.Bool("no-color")
+			decodeSecrets = c.Bool("decode-secrets")
+			scanUsages = c.Bool("usages")
 
 			path := "."
-			pattern := ""
-			if c.NArg() > 1 {
+			patterns := c.StringSlice("pattern")
+			excluded := c.StringSlice("not")
+			if c.IsSet("file") {
+				filePatterns, err := readPatternFile(c.String("file"))
+				if err != nil {
+					return err
+				}
+				patterns = append(patterns, filePatterns...)
+			}
+
+			if c.IsSet("pattern") || c.IsSet("file") {
+				// Patterns are given by flag, so the only argument is the path
+				if c.NArg() > 1 {
+					return fmt.Errorf("unexpected argument '%s', patterns are given by flag so only a path is expected", c.Args().Get(1))
+				}
+				if c.NArg() > 0 {
+					path = c.Args().Get(0)
+				}
+			} else if c.NArg() > 1 {
 				// If there are two or more arguments, first is pattern, second is path
-				pattern = c.Args().Get(0)
+				patterns = []string{c.Args().Get(0)}
 				path = c.Args().Get(1)
+			} else if c.NArg() == 1 && len(excluded) > 0 {
+				// Only exclusions are given, so the only argument is the path
+				path = c.Args().Get(0)
 			} else if c.NArg() == 1 {
 				// If there is only one argument, it's the pattern
-				pattern = c.Args().Get(0)
-			} else {
+				patterns = []string{c.Args().Get(0)}
+			} else if len(excluded) == 0 {
 				// No arguments provided
 				cli.ShowAppHelpAndExit(c, 1)
 			}
 
+			if len(patterns) == 0 && len(excluded) == 0 {
+				return fmt.Errorf("no patterns to search for")
+			}
+
 			fullPath, err := filepath.Abs(path)
 			if err != nil {
 				return err
 			}
 
-			regPattern, err := generateRegex(pattern)
+			options := patternOptions{
+				regex:         c.Bool("regex"),
+				caseSensitive: c.Bool("case-sensitive"),
+				ignoreCase:    c.Bool("ignore-case"),
+				wholeSegment:  c.Bool("whole-segment"),
+				exact:         c.Bool("exact"),
+				relaxed:       c.Bool("relaxed"),
+			}
+			matchers, err := newPatternMatchers(patterns, options, c.Bool("value"), c.Bool("pair"))
+			if err != nil {
+				return err
+			}
+			exclusions, err := newPatternMatchers(excluded, options, c.Bool("value"), c.Bool("pair"))
 			if err != nil {
-				log.Fatalf("Error generating regex: %s", err)
+				return err
 			}
+			matcher := NewQueryMatcher(matchers, c.Bool("all"), exclusions)
+			verbose("Matching entries with %s", matcher)
 
-			coloredPrintf(yellow, "Searching for pattern '%s' in %s\n\n", pattern, fullPath)
+			coloredPrintf(yellow, "Searching for %s in %s\n\n", describePatterns(patterns, c.Bool("all"), excluded), fullPath)
 
-			return searchHandler.Search(fullPath, regPattern, showHidden)
+			return searchHandler.Search(fullPath, matcher, showHidden)
 		},
 	}
 	return app
@@ -118,11 +230,83 @@ func isSupportedFileType(path string) bool {
 	return false
 }
 
-// generateRegex generates a case-insensitive regular expression from the given pattern.
-func generateRegex(pattern string) (*regexp.Regexp, error) {
-	pattern = "(?i)" + regexp.QuoteMeta(pattern)
-	re, err := regexp.Compile(pattern)
-	return re, err
+// segmentSeparator matches the characters that separate the segments of a key, or the start or end of the key.
+const segmentSeparator = `[._\-:/\[\]]`
+
+// highlightGroup is the name of the group holding the part of a match to highlight, where it is not the whole match.
+const highlightGroup = "varip"
+
+// patternOptions controls how the search pattern is compiled into a regular expression.
+type patternOptions struct {
+	// regex passes the pattern through as a regular expression, rather than matching it as a fixed string
+	regex bool
+	// caseSensitive and ignoreCase override the smart case default,
+	// which is case insensitive unless the pattern has an uppercase character
+	caseSensitive bool
+	ignoreCase    bool
+	// wholeSegment only matches whole segments of a key, e.g. url matches spring.datasource.url but not curlTimeout
+	wholeSegment bool
+	// exact only matches the full key
+	exact bool
+	// relaxed matches the pattern against the relaxed form of keys, so naming conventions don't matter, see relaxedKey
+	relaxed bool
+}
+
+// compilePattern compiles the search pattern into a regular expression, based on the given options.
+// An invalid regular expression returns an error describing what is wrong with it.
+func compilePattern(pattern string, options patternOptions) (*regexp.Regexp, error) {
+	expr := regexp.QuoteMeta(pattern)
+	if options.relaxed {
+		expr = regexp.QuoteMeta(relaxedKey(pattern))
+	}
+	if options.regex {
+		expr = pattern
+	}
+	if options.relaxed && options.wholeSegment {
+		return nil, fmt.Errorf("--relaxed and --whole-segment can not be used together, relaxed keys have no segments")
+	}
+
+	// The expression is checked on its own, so errors don't refer to the flags added to it
+	parsed, err := syntax.Parse(expr, syntax.Perl)
+	if err != nil {
+		var syntaxErr *syntax.Error
+		if errors.As(err, &syntaxErr) {
+			return nil, fmt.Errorf("invalid regular expression '%s': %s: `%s`", pattern, syntaxErr.Code, syntaxErr.Expr)
+		}
+		return nil, fmt.Errorf("invalid regular expression '%s': %s", pattern, err)
+	}
+
+	switch {
+	case options.exact:
+		expr = "^(?:" + expr + ")$"
+	case options.wholeSegment:
+		expr = fmt.Sprintf("(?:^|%s)(?P<%s>%s)(?:$|%s)", segmentSeparator, highlightGroup, expr, segmentSeparator)
+	}
+
+	// Relaxed keys are lowercase, so case never matters
+	if options.relaxed || options.ignoreCase || !options.caseSensitive && !hasUppercaseLiteral(parsed) {
+		expr = "(?i)" + expr
+	}
+
+	return regexp.Compile(expr)
+}
+
+// hasUppercaseLiteral reports whether the parsed expression has an uppercase literal character, for smart case matching.
+// Escapes such as \S or \W don't count.
+func hasUppercaseLiteral(re *syntax.Regexp) bool {
+	if re.Op == syntax.OpLiteral {
+		for _, r := range re.Rune {
+			if unicode.IsUpper(r) {
+				return true
+			}
+		}
+	}
+	for _, sub := range re.Sub {
+		if hasUppercaseLiteral(sub) {
+			return true
+		}
+	}
+	return false
 }
 
 // coloredPrintf prints the formatted string with or without color.
@@ -2,20 +2,19 @@ package main
 
 import (
 	"fmt"
-	"regexp"
 	"testing"
 )
 
 type MockSearchHandler struct {
 	Path       string
-	Pattern    *regexp.Regexp
+	Pattern    *Matcher
 	ShowHidden bool
 	CallCount  int
 }
 
-func (m *MockSearchHandler) Search(path string, pattern *regexp.Regexp, showHidden bool) error {
+func (m *MockSearchHandler) Search(path string, matcher *Matcher, showHidden bool) error {
 	m.Path = path
-	m.Pattern = pattern
+	m.Pattern = matcher
 	m.ShowHidden = showHidden
 	m.CallCount++
 	return nil
@@ -41,3 +40,184 @@ func TestCLIParsingOrder(t *testing.T) {
 		t.Errorf("Expected pattern to be %s, got %s", expectedPattern, mock.Pattern.String())
 	}
 }
+
+func TestCLIRegexMode(t *testing.T) {
+	mock := &MockSearchHandler{}
+	app := setupApp(mock)
+
+	err := app.Run([]string{"varip", "--regex", `^spring\.datasource\.(url|username)$`, "./path/testPath"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expectedPattern := `(?i)^spring\.datasource\.(url|username)$`
+	if mock.Pattern.String() != expectedPattern {
+		t.Errorf("Expected pattern to be %s, got %s", expectedPattern, mock.Pattern.String())
+	}
+}
+
+func TestCLIInvalidRegex(t *testing.T) {
+	mock := &MockSearchHandler{}
+	app := setupApp(mock)
+
+	err := app.Run([]string{"varip", "--regex", "_(KEY|TOKEN", "./path/testPath"})
+	if err == nil {
+		t.Fatalf("Expected error for invalid regular expression, got nil")
+	}
+
+	expectedError := "invalid regular expression '_(KEY|TOKEN': missing closing ): `_(KEY|TOKEN`"
+	if err.Error() != expectedError {
+		t.Errorf("Expected error to be %s, got %s", expectedError, err.Error())
+	}
+
+	if mock.CallCount != 0 {
+		t.Errorf("Expected search not to be called, got %d calls", mock.CallCount)
+	}
+}
+
+func TestCLIFixedStringDefault(t *testing.T) {
+	mock := &MockSearchHandler{}
+	app := setupApp(mock)
+
+	err := app.Run([]string{"varip", "db.(host", "./path/testPath"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !mock.Pattern.key.MatchString("DB.(HOST") || mock.Pattern.key.MatchString("db_host") {
+		t.Errorf("Expected pattern %s to match the fixed string only", mock.Pattern.String())
+	}
+}
+
+func TestCompilePattern(t *testing.T) {
+	testCases := []struct {
+		pattern   string
+		options   patternOptions
+		matches   []string
+		unmatched []string
+	}{
+		// Smart case is case insensitive for a lowercase pattern, and case sensitive otherwise
+		{pattern: "url", matches: []string{"REACT_APP_API_URL", "spring.datasource.url", "curlTimeout"}},
+		{pattern: "URL", matches: []string{"REACT_APP_API_URL"}, unmatched: []string{"spring.datasource.url"}},
+		{pattern: `\S+_URL`, options: patternOptions{regex: true}, matches: []string{"API_URL"}, unmatched: []string{"api_url"}},
+		{pattern: `\w+_url`, options: patternOptions{regex: true}, matches: []string{"API_URL", "api_url"}},
+		{pattern: "URL", options: patternOptions{ignoreCase: true}, matches: []string{"spring.datasource.url"}},
+		{pattern: "url", options: patternOptions{caseSensitive: true}, matches: []string{"spring.datasource.url"}, unmatched: []string{"REACT_APP_API_URL"}},
+		// Whole segment matching
+		{
+			pattern:   "url",
+			options:   patternOptions{wholeSegment: true},
+			matches:   []string{"REACT_APP_API_URL", "spring.datasource.url", "url", "servers.[0].url", "api-url-v2"},
+			unmatched: []string{"curlTimeout", "URLS", "spring.datasource.urls"},
+		},
+		{pattern: "datasource.url", options: patternOptions{wholeSegment: true}, matches: []string{"spring.datasource.url"}, unmatched: []string{"spring.datasource.url2"}},
+		// Exact matching
+		{
+			pattern:   "spring.datasource.url",
+			options:   patternOptions{exact: true},
+			matches:   []string{"spring.datasource.url", "SPRING.DATASOURCE.URL"},
+			unmatched: []string{"spring.datasource.url.x", "my.spring.datasource.url"},
+		},
+		{pattern: "db_(host|port)", options: patternOptions{regex: true, exact: true}, matches: []string{"DB_HOST", "db_port"}, unmatched: []string{"DB_HOST_2"}},
+	}
+
+	for _, testCase := range testCases {
+		re, err := compilePattern(testCase.pattern, testCase.options)
+		if err != nil {
+			t.Fatalf("Failed to compile pattern %s: %v", testCase.pattern, err)
+		}
+
+		for _, key := range testCase.matches {
+			if !re.MatchString(key) {
+				t.Errorf("Expected pattern %s (%+v) to match %s", testCase.pattern, testCase.options, key)
+			}
+		}
+		for _, key := range testCase.unmatched {
+			if re.MatchString(key) {
+				t.Errorf("Expected pattern %s (%+v) not to match %s", testCase.pattern, testCase.options, key)
+			}
+		}
+	}
+	// Relaxed keys have no separators, so there are no segments to match
+	if _, err := compilePattern("name", patternOptions{relaxed: true, wholeSegment: true}); err == nil {
+		t.Errorf("Expected error for --relaxed with --whole-segment, got nil")
+	}
+}
+
+func TestCLIMultiplePatterns(t *testing.T) {
+	mock := &MockSearchHandler{}
+	app := setupApp(mock)
+
+	err := app.Run([]string{"varip", "-e", "redis", "-e", "port,host", "--all", "--not", "test", "./path/testPath"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expectedPath := abs("./path/testPath")
+	if mock.Path != expectedPath {
+		t.Errorf("Expected path to be %s, got %s", expectedPath, mock.Path)
+	}
+
+	expectedPattern := `((?i)redis AND (?i)port,host) AND NOT (?i)test`
+	if mock.Pattern.String() != expectedPattern {
+		t.Errorf("Expected pattern to be %s, got %s", expectedPattern, mock.Pattern.String())
+	}
+}
+
+func TestCLIPatternFile(t *testing.T) {
+	mock := &MockSearchHandler{}
+	app := setupApp(mock)
+
+	err := app.Run([]string{"varip", "-f", "./testdata/unit/fixtures/patterns.txt", "-e", "token"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if mock.Path != abs(".") {
+		t.Errorf("Expected path to be %s, got %s", abs("."), mock.Path)
+	}
+
+	expectedPattern := `((?i)token OR (?i)password OR (?i)secret)`
+	if mock.Pattern.String() != expectedPattern {
+		t.Errorf("Expected pattern to be %s, got %s", expectedPattern, mock.Pattern.String())
+	}
+
+	err = setupApp(mock).Run([]string{"varip", "-f", "./testdata/unit/fixtures/missing.txt"})
+	if err == nil {
+		t.Errorf("Expected error for a missing patterns file, got nil")
+	}
+}
+
+func TestCLIOnlyExclusions(t *testing.T) {
+	mock := &MockSearchHandler{}
+	app := setupApp(mock)
+
+	err := app.Run([]string{"varip", "--not", "placeholder", "./path/testPath"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expectedPath := abs("./path/testPath")
+	if mock.Path != expectedPath {
+		t.Errorf("Expected path to be %s, got %s", expectedPath, mock.Path)
+	}
+
+	expectedPattern := `NOT (?i)placeholder`
+	if mock.Pattern.String() != expectedPattern {
+		t.Errorf("Expected pattern to be %s, got %s", expectedPattern, mock.Pattern.String())
+	}
+}
+
+func TestCLIExtraArguments(t *testing.T) {
+	mock := &MockSearchHandler{}
+	app := setupApp(mock)
+
+	err := app.Run([]string{"varip", "-e", "redis", "./path/testPath", "./path/other"})
+	if err == nil {
+		t.Errorf("Expected error for an argument after the path, got nil")
+	}
+
+	if mock.CallCount != 0 {
+		t.Errorf("Expected no search, got %d", mock.CallCount)
+	}
+}
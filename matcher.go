@@ -0,0 +1,268 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// matchAll matches every key, so parsers return all of their entries.
+var matchAll = regexp.MustCompile("")
+
+// Matcher decides which parsed entries are matches, by testing their key, their value or both.
+// Matchers can be composed with AND, OR and NOT, so any number of patterns is tested against the same parsed entries.
+type Matcher struct {
+	// key is matched against the key of an entry, nil matches any key
+	key *regexp.Regexp
+	// relaxed matches key against the relaxed form of keys, see relaxedKey
+	relaxed bool
+	// value is matched against the value of an entry, nil matches any value
+	value *regexp.Regexp
+
+	// all of these matchers must match (AND)
+	all []*Matcher
+	// at least one of these matchers must match (OR), if there are any
+	any []*Matcher
+	// none of these matchers may match (NOT)
+	none []*Matcher
+}
+
+// NewMatcher returns a Matcher for entries whose key matches key and value matches value, either of which can be nil.
+func NewMatcher(key, value *regexp.Regexp) *Matcher {
+	return &Matcher{key: key, value: value}
+}
+
+// NewQueryMatcher combines the matchers of several patterns. An entry must match any of the patterns,
+// or all of them if requireAll is set, and none of the excluded patterns.
+func NewQueryMatcher(patterns []*Matcher, requireAll bool, excluded []*Matcher) *Matcher {
+	if len(patterns) == 1 && len(excluded) == 0 {
+		return patterns[0]
+	}
+
+	if requireAll {
+		return &Matcher{all: patterns, none: excluded}
+	}
+	return &Matcher{any: patterns, none: excluded}
+}
+
+// Match reports whether the given entry is a match.
+func (m *Matcher) Match(match Match) bool {
+	if m.key != nil {
+		key := match.Key
+		if m.relaxed {
+			key = relaxedKey(key)
+		}
+		if !m.key.MatchString(key) {
+			return false
+		}
+	}
+	if m.value != nil && !m.value.MatchString(match.Value) {
+		return false
+	}
+
+	for _, matcher := range m.all {
+		if !matcher.Match(match) {
+			return false
+		}
+	}
+	for _, matcher := range m.none {
+		if matcher.Match(match) {
+			return false
+		}
+	}
+	if len(m.any) == 0 {
+		return true
+	}
+	for _, matcher := range m.any {
+		if matcher.Match(match) {
+			return true
+		}
+	}
+	return false
+}
+
+// Filter returns the entries that are matches.
+func (m *Matcher) Filter(matches []Match) []Match {
+	var filtered []Match
+	for _, match := range matches {
+		if m.Match(match) {
+			filtered = append(filtered, match)
+		}
+	}
+	return filtered
+}
+
+// highlights returns the key and value patterns whose matches are highlighted, which excludes NOT patterns.
+// Relaxed key patterns match the relaxed form of a key, which doesn't line up with the key itself, so they aren't highlighted.
+func (m *Matcher) highlights() (keys, values []*regexp.Regexp) {
+	if m.key != nil && !m.relaxed {
+		keys = append(keys, m.key)
+	}
+	if m.value != nil {
+		values = append(values, m.value)
+	}
+	for _, group := range [][]*Matcher{m.all, m.any} {
+		for _, matcher := range group {
+			k, v := matcher.highlights()
+			keys = append(keys, k...)
+			values = append(values, v...)
+		}
+	}
+	return keys, values
+}
+
+// String describes the matcher, e.g. (?i)ddl\-auto=(?i)update or ((?i)redis AND (?i)port) AND NOT (?i)test
+func (m *Matcher) String() string {
+	var parts []string
+	if m.key != nil || m.value != nil {
+		pattern := ""
+		if m.key != nil {
+			pattern = m.key.String()
+		}
+		if m.value != nil {
+			pattern += "=" + m.value.String()
+		}
+		parts = append(parts, pattern)
+	}
+	if len(m.all) > 0 {
+		parts = append(parts, joinMatchers(m.all, " AND "))
+	}
+	if len(m.any) > 0 {
+		parts = append(parts, joinMatchers(m.any, " OR "))
+	}
+	for _, matcher := range m.none {
+		parts = append(parts, "NOT "+matcher.String())
+	}
+	return strings.Join(parts, " AND ")
+}
+
+// joinMatchers describes the given matchers joined by op, in parentheses if there is more than one.
+func joinMatchers(matchers []*Matcher, op string) string {
+	var parts []string
+	for _, matcher := range matchers {
+		parts = append(parts, matcher.String())
+	}
+	if len(parts) == 1 {
+		return parts[0]
+	}
+	return "(" + strings.Join(parts, op) + ")"
+}
+
+// newPatternMatcher builds the matcher for a search pattern. By default the pattern is matched against keys,
+// inValue matches it against values instead, and pair splits a KEY=VALUE pattern at the first '=' to match both.
+func newPatternMatcher(pattern string, options patternOptions, inValue, pair bool) (*Matcher, error) {
+	// Relaxed binding only applies to keys
+	valueOptions := options
+	valueOptions.relaxed = false
+
+	switch {
+	case inValue && pair:
+		return nil, fmt.Errorf("--value and --pair can not be used together")
+	case inValue:
+		value, err := compilePattern(pattern, valueOptions)
+		if err != nil {
+			return nil, err
+		}
+		return NewMatcher(nil, value), nil
+	case pair:
+		keyPattern, valuePattern, found := strings.Cut(pattern, "=")
+		if !found {
+			return nil, fmt.Errorf("pattern '%s' is not of the form KEY=VALUE", pattern)
+		}
+		key, err := compilePattern(keyPattern, options)
+		if err != nil {
+			return nil, err
+		}
+		value, err := compilePattern(valuePattern, valueOptions)
+		if err != nil {
+			return nil, err
+		}
+		matcher := NewMatcher(key, value)
+		matcher.relaxed = options.relaxed
+		return matcher, nil
+	}
+
+	key, err := compilePattern(pattern, options)
+	if err != nil {
+		return nil, err
+	}
+	matcher := NewMatcher(key, nil)
+	matcher.relaxed = options.relaxed
+	return matcher, nil
+}
+
+// relaxedKey returns the relaxed form of a key, following Spring Boot's relaxed binding. Separators (., _, -, brackets
+// and other punctuation) are dropped and the rest is lowercased, so neither separators nor camelCase humps matter.
+// SPRING_DATASOURCE_URL, spring.datasource.url, spring.datasource-url and springDatasourceUrl all become springdatasourceurl,
+// and spring.main.log-startup-info and SPRING_MAIN_LOGSTARTUPINFO both become springmainlogstartupinfo
+func relaxedKey(key string) string {
+	var relaxed strings.Builder
+	for _, r := range key {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			relaxed.WriteRune(unicode.ToLower(r))
+		}
+	}
+	return relaxed.String()
+}
+
+// newPatternMatchers builds the matchers for the given patterns, see newPatternMatcher.
+func newPatternMatchers(patterns []string, options patternOptions, inValue, pair bool) ([]*Matcher, error) {
+	var matchers []*Matcher
+	for _, pattern := range patterns {
+		matcher, err := newPatternMatcher(pattern, options, inValue, pair)
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, matcher)
+	}
+	return matchers, nil
+}
+
+// describePatterns describes the search for the user, e.g. pattern 'redis' or patterns 'redis' and 'port', not 'test'
+func describePatterns(patterns []string, requireAll bool, excluded []string) string {
+	op := " or "
+	if requireAll {
+		op = " and "
+	}
+
+	var description string
+	switch len(patterns) {
+	case 0:
+		description = "everything"
+	case 1:
+		description = fmt.Sprintf("pattern '%s'", patterns[0])
+	default:
+		description = fmt.Sprintf("patterns '%s'", strings.Join(patterns, "'"+op+"'"))
+	}
+
+	if len(excluded) > 0 {
+		description += fmt.Sprintf(", not '%s'", strings.Join(excluded, "' or '"))
+	}
+	return description
+}
+
+// readPatternFile reads the patterns in the given file, one per line. Empty lines are skipped.
+func readPatternFile(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading patterns file: %w", err)
+	}
+	defer file.Close()
+
+	var patterns []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		pattern := strings.TrimSuffix(scanner.Text(), "\r")
+		if pattern != "" {
+			patterns = append(patterns, pattern)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading patterns file: %w", err)
+	}
+
+	return patterns, nil
+}
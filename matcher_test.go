@@ -0,0 +1,181 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestPatternMatcher(t *testing.T) {
+	entries := []Match{
+		{LineNum: 1, Key: "spring.jpa.hibernate.ddl-auto", Value: "update"},
+		{LineNum: 2, Key: "spring.jpa.hibernate.naming", Value: "update-strategy"},
+		{LineNum: 3, Key: "REDIS_HOST", Value: "redis.host.com"},
+		{LineNum: 4, Key: "cache.url", Value: "redis://redis.host.com:6379"},
+		{LineNum: 5, Key: "ddl-auto", Value: "none"},
+	}
+
+	testCases := []struct {
+		pattern  string
+		options  patternOptions
+		inValue  bool
+		pair     bool
+		expected []int
+	}{
+		{pattern: "redis", expected: []int{3}},
+		{pattern: "redis.host.com", inValue: true, expected: []int{3, 4}},
+		{pattern: "redis.host.com", options: patternOptions{exact: true}, inValue: true, expected: []int{3}},
+		{pattern: "ddl-auto=update", pair: true, expected: []int{1}},
+		{pattern: "hibernate=update", pair: true, expected: []int{1, 2}},
+		{pattern: "=none", pair: true, expected: []int{5}},
+	}
+
+	for _, testCase := range testCases {
+		matcher, err := newPatternMatcher(testCase.pattern, testCase.options, testCase.inValue, testCase.pair)
+		if err != nil {
+			t.Fatalf("Failed to build matcher for %s: %v", testCase.pattern, err)
+		}
+
+		matches := matcher.Filter(entries)
+		if len(matches) != len(testCase.expected) {
+			t.Fatalf("Expected %d matches for %s, got %d: %v", len(testCase.expected), testCase.pattern, len(matches), matches)
+		}
+
+		for i, match := range matches {
+			if match.LineNum != testCase.expected[i] {
+				t.Errorf("Expected match on line %d for %s, got %#v", testCase.expected[i], testCase.pattern, match)
+			}
+		}
+	}
+}
+
+func TestPatternMatcherErrors(t *testing.T) {
+	if _, err := newPatternMatcher("ddl-auto", patternOptions{}, false, true); err == nil {
+		t.Errorf("Expected error for a pair pattern without '=', got nil")
+	}
+	if _, err := newPatternMatcher("update", patternOptions{}, true, true); err == nil {
+		t.Errorf("Expected error for --value with --pair, got nil")
+	}
+}
+
+func TestQueryMatcher(t *testing.T) {
+	entries := []Match{
+		{LineNum: 1, Key: "DB_PASSWORD", Value: "s3cret"},
+		{LineNum: 2, Key: "DB_PASSWORD_PLACEHOLDER", Value: "changeme"},
+		{LineNum: 3, Key: "redis.port", Value: "6379"},
+		{LineNum: 4, Key: "redis.host", Value: "localhost"},
+		{LineNum: 5, Key: "API_SECRET", Value: "abc"},
+	}
+
+	testCases := []struct {
+		patterns   []string
+		requireAll bool
+		excluded   []string
+		expected   []int
+		describe   string
+	}{
+		{patterns: []string{"password"}, excluded: []string{"placeholder"}, expected: []int{1}, describe: "(?i)password AND NOT (?i)placeholder"},
+		{patterns: []string{"redis", "port"}, requireAll: true, expected: []int{3}, describe: "((?i)redis AND (?i)port)"},
+		{patterns: []string{"password", "secret"}, expected: []int{1, 2, 5}, describe: "((?i)password OR (?i)secret)"},
+		{patterns: []string{"password", "secret"}, excluded: []string{"placeholder", "api"}, expected: []int{1}, describe: "((?i)password OR (?i)secret) AND NOT (?i)placeholder AND NOT (?i)api"},
+		{excluded: []string{"db_", "redis"}, expected: []int{5}, describe: "NOT (?i)db_ AND NOT (?i)redis"},
+	}
+
+	for _, testCase := range testCases {
+		matchers, err := newPatternMatchers(testCase.patterns, patternOptions{}, false, false)
+		if err != nil {
+			t.Fatalf("Failed to build matchers: %v", err)
+		}
+		exclusions, err := newPatternMatchers(testCase.excluded, patternOptions{}, false, false)
+		if err != nil {
+			t.Fatalf("Failed to build matchers: %v", err)
+		}
+		matcher := NewQueryMatcher(matchers, testCase.requireAll, exclusions)
+
+		if matcher.String() != testCase.describe {
+			t.Errorf("Expected matcher %s, got %s", testCase.describe, matcher.String())
+		}
+
+		matches := matcher.Filter(entries)
+		if len(matches) != len(testCase.expected) {
+			t.Fatalf("Expected %d matches for %s, got %d: %v", len(testCase.expected), matcher, len(matches), matches)
+		}
+
+		for i, match := range matches {
+			if match.LineNum != testCase.expected[i] {
+				t.Errorf("Expected match on line %d for %s, got %#v", testCase.expected[i], matcher, match)
+			}
+		}
+	}
+}
+
+func TestRelaxedKey(t *testing.T) {
+	testCases := map[string]string{
+		"SPRING_DATASOURCE_URL":        "springdatasourceurl",
+		"spring.datasource.url":        "springdatasourceurl",
+		"spring.datasource-url":        "springdatasourceurl",
+		"springDatasourceUrl":          "springdatasourceurl",
+		"spring.main.log-startup-info": "springmainlogstartupinfo",
+		"SPRING_MAIN_LOGSTARTUPINFO":   "springmainlogstartupinfo",
+		"spring.main.logStartupInfo":   "springmainlogstartupinfo",
+		"my.list[0].name":              "mylist0name",
+		"MY_LIST_0_NAME":               "mylist0name",
+		"ConfigMap/app:DB_HOST":        "configmapappdbhost",
+		"services.api.url2":            "servicesapiurl2",
+		"spring..datasource__url":      "springdatasourceurl",
+	}
+
+	for key, expected := range testCases {
+		if relaxed := relaxedKey(key); relaxed != expected {
+			t.Errorf("Expected relaxed form of %s to be %s, got %s", key, expected, relaxed)
+		}
+	}
+}
+
+func TestRelaxedMatcher(t *testing.T) {
+	entries := []Match{
+		{LineNum: 1, Key: "SPRING_DATASOURCE_URL", Value: "jdbc:postgresql://db/app"},
+		{LineNum: 2, Key: "spring.datasource.url", Value: "jdbc:postgresql://db/app"},
+		{LineNum: 3, Key: "spring.datasource-url", Value: "jdbc:h2:mem"},
+		{LineNum: 4, Key: "springDatasourceUrl", Value: "jdbc:h2:mem"},
+		{LineNum: 5, Key: "spring.datasource.url-params", Value: "ssl=true"},
+		{LineNum: 6, Key: "spring.datasource.urls", Value: "none"},
+		{LineNum: 7, Key: "spring.main.log-startup-info", Value: "false"},
+		{LineNum: 8, Key: "SPRING_MAIN_LOGSTARTUPINFO", Value: "false"},
+		{LineNum: 9, Key: "spring.main.logStartupInfo", Value: "false"},
+	}
+
+	testCases := []struct {
+		pattern  string
+		options  patternOptions
+		pair     bool
+		expected []int
+	}{
+		{pattern: "SPRING_DATASOURCE_URL", options: patternOptions{relaxed: true}, expected: []int{1, 2, 3, 4, 5, 6}},
+		{pattern: "springDatasourceUrl", options: patternOptions{relaxed: true}, expected: []int{1, 2, 3, 4, 5, 6}},
+		{pattern: "spring.datasource-url", options: patternOptions{relaxed: true, exact: true}, expected: []int{1, 2, 3, 4}},
+		{pattern: "spring.main.log-startup-info", options: patternOptions{relaxed: true}, expected: []int{7, 8, 9}},
+		{pattern: "SPRING_MAIN_LOGSTARTUPINFO", options: patternOptions{relaxed: true, exact: true}, expected: []int{7, 8, 9}},
+		// Regular expressions are matched against the relaxed form as written
+		{pattern: `datasourceurls?$`, options: patternOptions{relaxed: true, regex: true}, expected: []int{1, 2, 3, 4, 6}},
+		{pattern: `datasource\.urls?$`, options: patternOptions{relaxed: true, regex: true}, expected: []int{}},
+		// Relaxed binding doesn't apply to values
+		{pattern: "DATASOURCE_URL=jdbc:h2", options: patternOptions{relaxed: true}, pair: true, expected: []int{3, 4}},
+	}
+
+	for _, testCase := range testCases {
+		matcher, err := newPatternMatcher(testCase.pattern, testCase.options, false, testCase.pair)
+		if err != nil {
+			t.Fatalf("Failed to build matcher for %s: %v", testCase.pattern, err)
+		}
+
+		matches := matcher.Filter(entries)
+		if len(matches) != len(testCase.expected) {
+			t.Fatalf("Expected %d matches for %s, got %d: %v", len(testCase.expected), testCase.pattern, len(matches), matches)
+		}
+
+		for i, match := range matches {
+			if match.LineNum != testCase.expected[i] {
+				t.Errorf("Expected match on line %d for %s, got %#v", testCase.expected[i], testCase.pattern, match)
+			}
+		}
+	}
+}
@@ -2,11 +2,15 @@ package main
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"regexp"
+	"sort"
 	"strings"
+	"unicode/utf8"
 
 	"gopkg.in/yaml.v3"
 )
@@ -16,73 +20,219 @@ type Match struct {
 	LineNum int
 	Key     string
 	Value   string
+	// Column is the column of the key on LineNum, where the parser supports it
+	Column int
+	// Scope optionally describes where in the file the match was defined, e.g. the Dockerfile instruction and stage
+	Scope string
+	// Usage marks a match where source code reads the variable, rather than a config file defining it
+	Usage bool
 }
 
+// dotenvLine matches a dotenv assignment, with an optional export prefix and either a '=' or ':' separator.
+var dotenvLine = regexp.MustCompile(`^\s*(?:export\s+)?([\w.-]+)\s*(?:=|:)\s*(.*)$`)
+
 // ParseEnvFile parses an env file and returns all matches.
-// Parses .env, .properties
+// Follows the common dotenv syntax: an optional export prefix, single, double or backtick quoted values (which can span
+// multiple lines), escapes in double quoted values, and comments starting with # on their own line or after a value.
+// Each match is reported on the line its assignment starts.
+// Parses .env
 func ParseEnvFile(filePath string, re *regexp.Regexp) ([]Match, error) {
-	file, err := os.Open(filePath)
+	file, err := os.ReadFile(filePath)
 	if err != nil {
 		return nil, err
 	}
-	defer file.Close()
 
-	scanner := bufio.NewScanner(file)
-	lineNum := 0
+	lines := strings.Split(strings.ReplaceAll(string(file), "\r\n", "\n"), "\n")
 
 	var matches []Match
 
-	for scanner.Scan() {
-		line := scanner.Text()
-		lineNum++
-		split := strings.SplitN(line, "=", 2)
+	for i := 0; i < len(lines); i++ {
+		lineNum := i + 1
 
-		if len(split) == 2 {
-			if re.MatchString(split[0]) {
-				matches = append(matches, Match{Path: filePath, LineNum: lineNum, Key: split[0], Value: split[1]})
-			}
+		groups := dotenvLine.FindStringSubmatch(lines[i])
+		if groups == nil || strings.HasPrefix(strings.TrimSpace(lines[i]), "#") {
+			continue
 		}
 
+		key := groups[1]
+		value, consumed := parseDotenvValue(groups[2], lines[i+1:])
+		i += consumed
+
+		if re.MatchString(key) {
+			matches = append(matches, Match{Path: filePath, LineNum: lineNum, Key: key, Value: value})
+		}
 	}
 
 	return matches, nil
 }
 
+// parseDotenvValue parses a (possibly quoted) dotenv value, reading from the following lines if a quoted value spans them.
+// Returns the value and the number of following lines that were consumed.
+func parseDotenvValue(raw string, following []string) (string, int) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", 0
+	}
+
+	quote := raw[0]
+	if quote != '"' && quote != '\'' && quote != '`' {
+		// Unquoted values end at a comment preceded by whitespace
+		for i := 1; i < len(raw); i++ {
+			if raw[i] == '#' && (raw[i-1] == ' ' || raw[i-1] == '\t') {
+				return strings.TrimSpace(raw[:i]), 0
+			}
+		}
+		return raw, 0
+	}
+
+	value := raw[1:]
+	for consumed := 0; ; consumed++ {
+		if end := dotenvClosingQuote(value, quote); end >= 0 {
+			value = value[:end]
+			if quote == '"' {
+				value = unescapeDotenv(value)
+			}
+			return value, consumed
+		}
+		if consumed == len(following) {
+			// Unterminated quotes are kept as part of an unquoted value
+			return raw, 0
+		}
+		value += "\n" + following[consumed]
+	}
+}
+
+// dotenvClosingQuote returns the index of the closing quote, skipping escaped quotes in double quoted values.
+func dotenvClosingQuote(value string, quote byte) int {
+	for i := 0; i < len(value); i++ {
+		if value[i] == '\\' && quote == '"' {
+			i++
+			continue
+		}
+		if value[i] == quote {
+			return i
+		}
+	}
+	return -1
+}
+
+// unescapeDotenv decodes the \n, \r, \t, \" and \\ escapes of a double quoted value, other escapes are kept as is.
+func unescapeDotenv(value string) string {
+	replacer := strings.NewReplacer(`\n`, "\n", `\r`, "\r", `\t`, "\t", `\"`, `"`, `\\`, `\`)
+	return replacer.Replace(value)
+}
+
 // ParseJSONFile parses a JSON file and returns all matches.
-// Parses .json
+// Files that commonly contain comments and trailing commas (see jsoncFileTypes) are parsed in tolerant JSONC/JSON5 mode,
+// which is also used as a fallback when strict parsing fails.
+// Parses .json, .jsonc, .json5
 func ParseJSONFile(filePath string, re *regexp.Regexp) ([]Match, error) {
-	file, err := os.Open(filePath)
+	jsonBlob, err := os.ReadFile(filePath)
 	if err != nil {
 		return nil, err
 	}
-	defer file.Close()
 
-	jsonBlob, err := os.ReadFile(filePath)
+	var flattened []Match
+	if isJSONCFile(filePath) {
+		flattened, err = flattenJSONC(jsonBlob)
+	} else if flattened, err = flattenJSONDocument(newJSONDecoder(jsonBlob, jsonBlob, nil)); err != nil {
+		verbose("Error parsing %s as strict JSON, retrying as JSONC: %s", filePath, err)
+		if tolerant, jsoncErr := flattenJSONC(jsonBlob); jsoncErr == nil {
+			flattened, err = tolerant, nil
+		}
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	var jsonData map[string]interface{}
-	err = json.Unmarshal(jsonBlob, &jsonData)
+	var matches []Match
+	for _, entry := range flattened {
+		if re.MatchString(entry.Key) {
+			entry.Path = filePath
+			matches = append(matches, entry)
+		}
+	}
+
+	return matches, nil
+}
+
+// ParseNDJSONFile parses a newline delimited JSON (JSON Lines) file and returns all matches.
+// Each line is a record of its own, flattened with the record index as its root key (e.g. [12].field).
+// Malformed records are reported as errors and skipped, the rest of the file is still searched.
+// Parses .jsonl, .ndjson
+func ParseNDJSONFile(filePath string, re *regexp.Regexp) ([]Match, error) {
+	file, err := os.Open(filePath)
 	if err != nil {
 		return nil, err
 	}
+	defer file.Close()
 
-	flattened := make(map[string]interface{})
-	flattenJSON("", jsonData, flattened)
+	scanner := bufio.NewScanner(file)
+	// Records can be much longer than the default line limit
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	lineNum := 0
+	record := 0
 
 	var matches []Match
-	for k, v := range flattened {
-		if re.MatchString(k) {
-			matches = append(matches, Match{Path: filePath, Key: k, Value: fmt.Sprintf("%v", v)})
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		lineNum++
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
 		}
 
+		var flattened []Match
+		decoder := newJSONDecoder(line, line, nil)
+		err := flattenJSON(fmt.Sprintf("[%d].", record), decoder, lineNum, 1, &flattened)
+		record++
+		if err != nil {
+			handleError(fmt.Errorf("line %d: %w", lineNum, err), filePath)
+			verbose("Skipping malformed record on line %d of %s: %s", lineNum, filePath, err)
+			continue
+		}
+
+		for _, entry := range flattened {
+			if re.MatchString(entry.Key) {
+				entry.Path = filePath
+				entry.LineNum = lineNum
+				matches = append(matches, entry)
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
 	}
 
 	return matches, nil
 }
 
+// flattenJSONC converts a JSONC/JSON5 document to strict JSON and flattens it, reporting positions in the original document.
+func flattenJSONC(src []byte) ([]Match, error) {
+	normalized, offsets, err := normalizeJSONC(src)
+	if err != nil {
+		return nil, err
+	}
+	return flattenJSONDocument(newJSONDecoder(normalized, src, offsets))
+}
+
+// flattenJSONDocument flattens the single top-level JSON value read from the decoder.
+// Any root type is accepted, arrays at the root are flattened as [0].key.
+func flattenJSONDocument(decoder *jsonDecoder) ([]Match, error) {
+	var flattened []Match
+	if err := flattenJSON("", decoder, 0, 0, &flattened); err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, fmt.Errorf("json: invalid data after top-level value")
+	}
+
+	return flattened, nil
+}
+
 // ParseYAMLFile parses the YAML file, flattens it, and finds matches based on the given regexp.
+// Every document of a multi-document file is searched, and its matches are scoped to the document index (and kind/name where present).
 // Parses .yml, .yaml
 func ParseYAMLFile(filePath string, re *regexp.Regexp) ([]Match, error) {
 	file, err := os.ReadFile(filePath)
@@ -90,57 +240,281 @@ func ParseYAMLFile(filePath string, re *regexp.Regexp) ([]Match, error) {
 		return nil, err
 	}
 
-	var data interface{} // Use interface{} to handle arrays at the root
-	if err = yaml.Unmarshal(file, &data); err != nil {
+	documents, err := decodeYAMLDocuments(file)
+	if err != nil {
 		return nil, err
 	}
 
-	flattened := make(map[string]string)
-	flattenYAML("", data, flattened)
+	return flattenYAMLDocuments(filePath, documents, re), nil
+}
+
+// decodeYAMLDocuments decodes every document of a (possibly multi-document) YAML file.
+func decodeYAMLDocuments(file []byte) ([]*yaml.Node, error) {
+	var documents []*yaml.Node
+	decoder := yaml.NewDecoder(bytes.NewReader(file))
+	for {
+		var document yaml.Node
+		if err := decoder.Decode(&document); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		documents = append(documents, &document)
+	}
+	return documents, nil
+}
 
+// flattenYAMLDocuments flattens the decoded documents of a YAML file and finds matches based on the given regexp.
+func flattenYAMLDocuments(filePath string, documents []*yaml.Node, re *regexp.Regexp) []Match {
 	var matches []Match
-	for k, v := range flattened {
-		if re.MatchString(k) {
-			matches = append(matches, Match{Path: filePath, Key: k, Value: v})
+	for i, document := range documents {
+		var flattened []Match
+		if !flattenKubernetesResource(document, &flattened) {
+			flattenYAML("", document, nil, &flattened)
+		}
+
+		scope := ""
+		if len(documents) > 1 {
+			scope = yamlDocumentScope(i, document)
+		}
+
+		for _, entry := range flattened {
+			if re.MatchString(entry.Key) {
+				entry.Path = filePath
+				entry.Scope = scope
+				matches = append(matches, entry)
+			}
 		}
 	}
 
-	return matches, nil
+	return matches
 }
 
-// flattenYAML converts a nested YAML structure into a flat key-value map.
+// yamlDocumentScope describes a document of a multi-document YAML file by its index, and its kind/name where present.
+func yamlDocumentScope(index int, document *yaml.Node) string {
+	scope := fmt.Sprintf("document %d", index)
 
-func flattenYAML(prefix string, value interface{}, flattened map[string]string) {
-	switch child := value.(type) {
-	case map[string]interface{}:
-		for k, v := range child {
-			flattenYAML(prefix+k+".", v, flattened)
+	var parts []string
+	if kind := yamlMappingValue(yamlRoot(document), "kind"); kind != nil {
+		parts = append(parts, kind.Value)
+	}
+	if name := yamlMappingValue(yamlMappingValue(yamlRoot(document), "metadata"), "name"); name != nil {
+		parts = append(parts, name.Value)
+	}
+	if len(parts) > 0 {
+		scope += ", " + strings.Join(parts, "/")
+	}
+
+	return scope
+}
+
+// flattenYAML converts a nested YAML node into flat key-value entries, positioned at the key that defines each value.
+// keyNode is the mapping key the node is the value of, or nil for documents and sequence items.
+func flattenYAML(prefix string, node *yaml.Node, keyNode *yaml.Node, flattened *[]Match) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			flattenYAML(prefix, child, nil, flattened)
+		}
+	case yaml.AliasNode:
+		flattenYAML(prefix, node.Alias, keyNode, flattened)
+	case yaml.MappingNode, yaml.SequenceNode:
+		if isCustomYAMLTag(node.Tag) && prefix != "" {
+			// Tagged collections (e.g. !GetAtt [Db, Arn]) are reported as a single value, like tagged scalars
+			*flattened = append(*flattened, yamlEntry(prefix, yamlFlowValue(node), node, keyNode))
+			return
 		}
-	case []interface{}:
-		for i, v := range child {
-			flattenYAML(fmt.Sprintf("%s[%d].", prefix, i), v, flattened)
+		if node.Kind == yaml.MappingNode {
+			for _, pair := range yamlMappingPairs(node) {
+				flattenYAML(prefix+pair[0].Value+".", pair[1], pair[0], flattened)
+			}
+			return
 		}
-	case map[interface{}]interface{}: // YAML parsing might produce this
-		for k, v := range child {
-			flattenYAML(prefix+fmt.Sprintf("%v.", k), v, flattened)
+		for i, v := range node.Content {
+			flattenYAML(fmt.Sprintf("%s[%d].", prefix, i), v, nil, flattened)
 		}
 	default:
-		flattened[prefix[:len(prefix)-1]] = fmt.Sprintf("%v", value) // Convert value to string
+		if prefix == "" {
+			// Scalar documents have no key to report
+			return
+		}
+		value := node.Value
+		if isCustomYAMLTag(node.Tag) {
+			value = node.Tag + " " + value
+		}
+		*flattened = append(*flattened, yamlEntry(prefix, value, node, keyNode))
 	}
 }
 
-// flattenJSON flattens a JSON object into a map.
-func flattenJSON(prefix string, value interface{}, flattened map[string]interface{}) {
-	switch child := value.(type) {
-	case map[string]interface{}:
-		for k, v := range child {
-			flattenJSON(prefix+k+".", v, flattened)
+// yamlEntry creates the flattened entry for a value, positioned at its key if it has one.
+func yamlEntry(prefix, value string, node *yaml.Node, keyNode *yaml.Node) Match {
+	position := node
+	if keyNode != nil {
+		position = keyNode
+	}
+	return Match{LineNum: position.Line, Column: position.Column, Key: prefix[:len(prefix)-1], Value: value}
+}
+
+// yamlMappingPairs returns the key/value pairs of a mapping node, with the pairs of any merged (<<) mappings resolved.
+// Keys defined in the mapping itself take precedence over merged keys, and earlier merged mappings over later ones.
+func yamlMappingPairs(node *yaml.Node) [][2]*yaml.Node {
+	defined := make(map[string]bool)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Tag != "!!merge" {
+			defined[node.Content[i].Value] = true
+		}
+	}
+
+	var pairs [][2]*yaml.Node
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		k, v := node.Content[i], node.Content[i+1]
+		if k.Tag != "!!merge" {
+			pairs = append(pairs, [2]*yaml.Node{k, v})
+			continue
+		}
+
+		merged := []*yaml.Node{v}
+		if v = yamlResolveAlias(v); v.Kind == yaml.SequenceNode {
+			merged = v.Content
 		}
-	case []interface{}:
-		for i, v := range child {
-			flattenJSON(fmt.Sprintf("%s[%d].", prefix, i), v, flattened)
+		for _, m := range merged {
+			if m = yamlResolveAlias(m); m.Kind != yaml.MappingNode {
+				continue
+			}
+			for _, pair := range yamlMappingPairs(m) {
+				if !defined[pair[0].Value] {
+					defined[pair[0].Value] = true
+					pairs = append(pairs, pair)
+				}
+			}
 		}
+	}
+
+	return pairs
+}
+
+// yamlResolveAlias returns the node an alias points to, or the node itself if it is not an alias.
+func yamlResolveAlias(node *yaml.Node) *yaml.Node {
+	for node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	return node
+}
+
+// isCustomYAMLTag reports whether the tag is an application specific tag (e.g. CloudFormation !Ref), rather than a standard !!tag.
+func isCustomYAMLTag(tag string) bool {
+	return strings.HasPrefix(tag, "!") && !strings.HasPrefix(tag, "!!")
+}
+
+// yamlFlowValue renders a node in YAML flow style, including its tag, e.g. !GetAtt [Db, Arn].
+func yamlFlowValue(node *yaml.Node) string {
+	flow := *node
+	flow.Style |= yaml.FlowStyle
+	out, err := yaml.Marshal(&flow)
+	if err != nil {
+		return node.Tag
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// yamlRoot returns the root node of a YAML document.
+func yamlRoot(document *yaml.Node) *yaml.Node {
+	if document.Kind == yaml.DocumentNode && len(document.Content) > 0 {
+		return document.Content[0]
+	}
+	return document
+}
+
+// yamlMappingValue returns the value node for the given key of a mapping node, or nil if it is not present.
+func yamlMappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// flattenJSON flattens the next JSON value read from the decoder into entries.
+// Scalar values are positioned at the given line and column, which is the start of their key (or array element).
+func flattenJSON(prefix string, decoder *jsonDecoder, line, column int, flattened *[]Match) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	switch token {
+	case json.Delim('{'):
+		for decoder.More() {
+			line, column := decoder.position()
+			key, err := decoder.Token()
+			if err != nil {
+				return err
+			}
+			if err = flattenJSON(prefix+key.(string)+".", decoder, line, column, flattened); err != nil {
+				return err
+			}
+		}
+		_, err = decoder.Token()
+		return err
+	case json.Delim('['):
+		for i := 0; decoder.More(); i++ {
+			line, column := decoder.position()
+			if err = flattenJSON(fmt.Sprintf("%s[%d].", prefix, i), decoder, line, column, flattened); err != nil {
+				return err
+			}
+		}
+		_, err = decoder.Token()
+		return err
 	default:
-		flattened[prefix[:len(prefix)-1]] = value
+		if prefix == "" {
+			// Scalar documents have no key to report
+			return nil
+		}
+		*flattened = append(*flattened, Match{LineNum: line, Column: column, Key: prefix[:len(prefix)-1], Value: fmt.Sprintf("%v", token)})
+		return nil
 	}
 }
+
+// jsonDecoder is a token based JSON decoder, that can report the line and column of the next token.
+type jsonDecoder struct {
+	*json.Decoder
+	src []byte
+	// original is the document src was converted from, positions are reported in original
+	original []byte
+	// offsets maps each byte of src to its offset in original, nil if src is the original document
+	offsets []int
+	// lineStarts holds the offset of the first byte of each line of original
+	lineStarts []int
+}
+
+func newJSONDecoder(src, original []byte, offsets []int) *jsonDecoder {
+	lineStarts := []int{0}
+	for i, b := range original {
+		if b == '\n' {
+			lineStarts = append(lineStarts, i+1)
+		}
+	}
+	return &jsonDecoder{Decoder: json.NewDecoder(bytes.NewReader(src)), src: src, original: original, offsets: offsets, lineStarts: lineStarts}
+}
+
+// position returns the line and column of the next token, skipping any whitespace and separators before it.
+func (d *jsonDecoder) position() (int, int) {
+	offset := int(d.InputOffset())
+	for offset < len(d.src) && strings.IndexByte(" \t\r\n,:", d.src[offset]) >= 0 {
+		offset++
+	}
+	if d.offsets != nil {
+		if offset < len(d.offsets) {
+			offset = d.offsets[offset]
+		} else {
+			offset = len(d.original)
+		}
+	}
+
+	line := sort.Search(len(d.lineStarts), func(i int) bool { return d.lineStarts[i] > offset })
+	return line, utf8.RuneCount(d.original[d.lineStarts[line-1]:offset]) + 1
+}
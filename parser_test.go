@@ -3,6 +3,7 @@ package main
 import (
 	"log"
 	"path/filepath"
+	"regexp"
 	"runtime"
 	"testing"
 	"time"
@@ -14,6 +15,33 @@ type testCaseParseFile struct {
 	expectedMatches []Match
 }
 
+// assertParsedMatches runs parse on every test case, checking it returns exactly the expected matches, in order.
+func assertParsedMatches(t *testing.T, parse func(string, *regexp.Regexp) ([]Match, error), testCases []testCaseParseFile) {
+	t.Helper()
+
+	for _, testCase := range testCases {
+		re, err := compilePattern(testCase.searchPattern, patternOptions{ignoreCase: true})
+		if err != nil {
+			t.Fatalf("Failed to compile regex pattern: %v", err)
+		}
+
+		matches, err := parse(testCase.filePath, re)
+		if err != nil {
+			t.Fatalf("Parsing %s returned an error: %v", testCase.filePath, err)
+		}
+
+		if len(matches) != len(testCase.expectedMatches) {
+			t.Fatalf("Expected %d matches, got %d: %v", len(testCase.expectedMatches), len(matches), matches)
+		}
+
+		for i, match := range matches {
+			if match != testCase.expectedMatches[i] {
+				t.Errorf("Expected match %#v, got %#v at index %d", testCase.expectedMatches[i], match, i)
+			}
+		}
+	}
+}
+
 // abs returns the absolute path of the given file path, relative to the current file.
 func abs(filePath string) string {
 	_, filename, _, _ := runtime.Caller(0)
@@ -27,15 +55,15 @@ func TestParseYamlFile(t *testing.T) {
 			filePath:      abs("./testdata/unit/fixtures/unit.yaml"),
 			searchPattern: "resources",
 			expectedMatches: []Match{
-				{Path: abs("./testdata/unit/fixtures/unit.yaml"), LineNum: 0, Key: "app.deployment.resources.cpuRequest", Value: "200m"},
-				{Path: abs("./testdata/unit/fixtures/unit.yaml"), LineNum: 0, Key: "app.deployment.resources.memoryLimit", Value: "768Mi"},
-				{Path: abs("./testdata/unit/fixtures/unit.yaml"), LineNum: 0, Key: "app.deployment.resources.memoryRequest", Value: "512Mi"},
+				{Path: abs("./testdata/unit/fixtures/unit.yaml"), LineNum: 11, Column: 7, Key: "app.deployment.resources.cpuRequest", Value: "200m"},
+				{Path: abs("./testdata/unit/fixtures/unit.yaml"), LineNum: 13, Column: 7, Key: "app.deployment.resources.memoryLimit", Value: "768Mi"},
+				{Path: abs("./testdata/unit/fixtures/unit.yaml"), LineNum: 12, Column: 7, Key: "app.deployment.resources.memoryRequest", Value: "512Mi"},
 			},
 		},
 	}
 
 	for _, testCase := range testCases {
-		re, err := generateRegex(testCase.searchPattern)
+		re, err := compilePattern(testCase.searchPattern, patternOptions{ignoreCase: true})
 		if err != nil {
 			t.Fatalf("Failed to compile regex pattern: %v", err)
 		}
@@ -61,22 +89,102 @@ func TestParseYamlFile(t *testing.T) {
 
 }
 
+func TestParseMultiDocumentYamlFile(t *testing.T) {
+	path := abs("./testdata/unit/fixtures/kubernetes/bundle.yaml")
+	testCases := []testCaseParseFile{
+		{
+			filePath:      path,
+			searchPattern: "replicas",
+			expectedMatches: []Match{
+				{Path: path, LineNum: 6, Column: 3, Key: "spec.replicas", Value: "2", Scope: "document 0, Deployment/api"},
+			},
+		},
+		{
+			filePath:      path,
+			searchPattern: "api_key",
+			expectedMatches: []Match{
+				{Path: path, LineNum: 14, Column: 3, Key: "Secret/api-creds:API_KEY", Value: "secret", Scope: "document 1, Secret/api-creds"},
+			},
+		},
+		{
+			filePath:      path,
+			searchPattern: "port",
+			expectedMatches: []Match{
+				{Path: path, LineNum: 20, Column: 3, Key: "server.port", Value: "8081", Scope: "document 3"},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		re, err := compilePattern(testCase.searchPattern, patternOptions{ignoreCase: true})
+		if err != nil {
+			t.Fatalf("Failed to compile regex pattern: %v", err)
+		}
+
+		matches, err := ParseYAMLFile(testCase.filePath, re)
+		if err != nil {
+			t.Fatalf("ParseYAMLFile returned an error: %v", err)
+		}
+
+		if len(matches) != len(testCase.expectedMatches) {
+			t.Errorf("Expected %d matches, got %d", len(testCase.expectedMatches), len(matches))
+		}
+
+		for _, match := range matches {
+			if !contains(testCase.expectedMatches, match) {
+				t.Errorf("Expected match %v in expected matches: %v", match, testCase.expectedMatches)
+			}
+		}
+	}
+}
+
+func TestParseYamlFileAnchorsAndTags(t *testing.T) {
+	path := abs("./testdata/unit/fixtures/cloudformation.yaml")
+	testCases := []testCaseParseFile{
+		{
+			filePath:      path,
+			searchPattern: "Database.Properties",
+			expectedMatches: []Match{
+				{Path: path, LineNum: 2, Column: 3, Key: "Resources.Database.Properties.engine", Value: "postgres"},
+				{Path: path, LineNum: 5, Column: 5, Key: "Resources.Database.Properties.tags.team", Value: "platform"},
+				{Path: path, LineNum: 12, Column: 7, Key: "Resources.Database.Properties.port", Value: "6432"},
+				{Path: path, LineNum: 13, Column: 7, Key: "Resources.Database.Properties.MasterUserPassword", Value: "!Ref DbPassword"},
+				{Path: path, LineNum: 14, Column: 7, Key: "Resources.Database.Properties.DBName", Value: "!Sub ${AWS::StackName}-db"},
+				{Path: path, LineNum: 15, Column: 7, Key: "Resources.Database.Properties.Endpoint", Value: "!GetAtt [Database, Endpoint.Address]"},
+			},
+		},
+		{
+			filePath:      path,
+			searchPattern: "Replica",
+			expectedMatches: []Match{
+				{Path: path, LineNum: 2, Column: 3, Key: "Resources.Replica.Properties.engine", Value: "postgres"},
+				{Path: path, LineNum: 3, Column: 3, Key: "Resources.Replica.Properties.port", Value: "5432"},
+				{Path: path, LineNum: 5, Column: 5, Key: "Resources.Replica.Properties.tags.team", Value: "platform"},
+				{Path: path, LineNum: 18, Column: 39, Key: "Resources.Replica.Properties.region", Value: "eu-west-2"},
+				{Path: path, LineNum: 5, Column: 5, Key: "Resources.Replica.Properties.labels.team", Value: "platform"},
+			},
+		},
+	}
+
+	assertParsedMatches(t, ParseYAMLFile, testCases)
+}
+
 func TestParseJsonFile(t *testing.T) {
 	testCases := []testCaseParseFile{
 		{
 			filePath:      abs("./testdata/unit/fixtures/unit.json"),
 			searchPattern: "database",
 			expectedMatches: []Match{
-				{Path: abs("./testdata/unit/fixtures/unit.json"), LineNum: 0, Key: "database.host", Value: "localhost"},
-				{Path: abs("./testdata/unit/fixtures/unit.json"), LineNum: 0, Key: "database.password", Value: "secret"},
-				{Path: abs("./testdata/unit/fixtures/unit.json"), LineNum: 0, Key: "database.port", Value: "5432"},
-				{Path: abs("./testdata/unit/fixtures/unit.json"), LineNum: 0, Key: "database.user", Value: "admin"},
+				{Path: abs("./testdata/unit/fixtures/unit.json"), LineNum: 3, Column: 9, Key: "database.host", Value: "localhost"},
+				{Path: abs("./testdata/unit/fixtures/unit.json"), LineNum: 6, Column: 9, Key: "database.password", Value: "secret"},
+				{Path: abs("./testdata/unit/fixtures/unit.json"), LineNum: 4, Column: 9, Key: "database.port", Value: "5432"},
+				{Path: abs("./testdata/unit/fixtures/unit.json"), LineNum: 5, Column: 9, Key: "database.user", Value: "admin"},
 			},
 		},
 	}
 
 	for _, testCase := range testCases {
-		re, err := generateRegex(testCase.searchPattern)
+		re, err := compilePattern(testCase.searchPattern, patternOptions{ignoreCase: true})
 		if err != nil {
 			t.Fatalf("Failed to compile regex pattern: %v", err)
 		}
@@ -100,6 +208,71 @@ func TestParseJsonFile(t *testing.T) {
 	}
 }
 
+func TestParseJsonFileArrayRoot(t *testing.T) {
+	path := abs("./testdata/unit/fixtures/unit-array.json")
+	testCase := testCaseParseFile{
+		filePath:      path,
+		searchPattern: "name",
+		expectedMatches: []Match{
+			{Path: path, LineNum: 3, Column: 5, Key: "[0].name", Value: "api"},
+			{Path: path, LineNum: 5, Column: 9, Key: "[0].environment.[0].name", Value: "DB_HOST"},
+		},
+	}
+
+	re, err := compilePattern(testCase.searchPattern, patternOptions{ignoreCase: true})
+	if err != nil {
+		t.Fatalf("Failed to compile regex pattern: %v", err)
+	}
+
+	matches, err := ParseJSONFile(testCase.filePath, re)
+	if err != nil {
+		t.Fatalf("ParseJSONFile returned an error: %v", err)
+	}
+
+	if len(matches) != len(testCase.expectedMatches) {
+		t.Fatalf("Expected %d matches, got %d: %v", len(testCase.expectedMatches), len(matches), matches)
+	}
+
+	for i, match := range matches {
+		if match != testCase.expectedMatches[i] {
+			t.Errorf("Expected match %#v, got %#v at index %d", testCase.expectedMatches[i], match, i)
+		}
+	}
+}
+
+func TestParseNdjsonFile(t *testing.T) {
+	path := abs("./testdata/unit/fixtures/unit.ndjson")
+	testCases := []testCaseParseFile{
+		{
+			filePath:      path,
+			searchPattern: "value",
+			expectedMatches: []Match{
+				{Path: path, LineNum: 1, Column: 21, Key: "[0].value", Value: "db.internal"},
+				{Path: path, LineNum: 3, Column: 21, Key: "[1].value", Value: "5432"},
+				// The malformed record on line 5 is skipped
+				{Path: path, LineNum: 6, Column: 21, Key: "[4].value", Value: "app"},
+			},
+		},
+		{
+			filePath:      path,
+			searchPattern: "[1].tags",
+			expectedMatches: []Match{
+				{Path: path, LineNum: 3, Column: 45, Key: "[1].tags.[0]", Value: "db"},
+				{Path: path, LineNum: 3, Column: 51, Key: "[1].tags.[1]", Value: "port"},
+			},
+		},
+		{
+			filePath:      path,
+			searchPattern: "[2]",
+			expectedMatches: []Match{
+				{Path: path, LineNum: 4, Column: 1, Key: "[2]", Value: "not an object"},
+			},
+		},
+	}
+
+	assertParsedMatches(t, ParseNDJSONFile, testCases)
+}
+
 // TestPerformanceParseJsonFile tests the performance of the ParseJSONFile function.
 // It should complete in less than 10 milliseconds.
 func TestPerformanceParseJsonFile(t *testing.T) {
@@ -108,20 +281,20 @@ func TestPerformanceParseJsonFile(t *testing.T) {
 			filePath:      abs("./testdata/unit/performance/large.json"),
 			searchPattern: "car",
 			expectedMatches: []Match{
-				{Path: abs("./testdata/unit/performance/large.json"), LineNum: 0, Key: "ago.throughout.carbon", Value: "2.30941927e+08"},
-				{Path: abs("./testdata/unit/performance/large.json"), LineNum: 0, Key: "ago.throughout.carry", Value: "favorite"},
-				{Path: abs("./testdata/unit/performance/large.json"), LineNum: 0, Key: "ago.careful", Value: "false"},
-				{Path: abs("./testdata/unit/performance/large.json"), LineNum: 0, Key: "carbon", Value: "false"},
-				{Path: abs("./testdata/unit/performance/large.json"), LineNum: 0, Key: "ago.carry", Value: "false"},
-				{Path: abs("./testdata/unit/performance/large.json"), LineNum: 0, Key: "car", Value: "true"},
-				{Path: abs("./testdata/unit/performance/large.json"), LineNum: 0, Key: "ago.throughout.carried", Value: "moon"},
-				{Path: abs("./testdata/unit/performance/large.json"), LineNum: 0, Key: "careful", Value: "joy"},
+				{Path: abs("./testdata/unit/performance/large.json"), LineNum: 356, Column: 13, Key: "ago.throughout.carbon", Value: "2.30941927e+08"},
+				{Path: abs("./testdata/unit/performance/large.json"), LineNum: 269, Column: 13, Key: "ago.throughout.carry", Value: "favorite"},
+				{Path: abs("./testdata/unit/performance/large.json"), LineNum: 767, Column: 9, Key: "ago.careful", Value: "false"},
+				{Path: abs("./testdata/unit/performance/large.json"), LineNum: 1142, Column: 5, Key: "carbon", Value: "false"},
+				{Path: abs("./testdata/unit/performance/large.json"), LineNum: 879, Column: 9, Key: "ago.carry", Value: "false"},
+				{Path: abs("./testdata/unit/performance/large.json"), LineNum: 1535, Column: 5, Key: "car", Value: "true"},
+				{Path: abs("./testdata/unit/performance/large.json"), LineNum: 205, Column: 13, Key: "ago.throughout.carried", Value: "moon"},
+				{Path: abs("./testdata/unit/performance/large.json"), LineNum: 1299, Column: 5, Key: "careful", Value: "joy"},
 			},
 		},
 	}
 
 	for _, testCase := range testCases {
-		re, err := generateRegex(testCase.searchPattern)
+		re, err := compilePattern(testCase.searchPattern, patternOptions{ignoreCase: true})
 		if err != nil {
 			t.Fatalf("Failed to compile regex pattern: %v", err)
 		}
@@ -165,14 +338,14 @@ func TestParsePropertiesFile(t *testing.T) {
 		},
 	}
 
-	re, err := generateRegex(testCase.searchPattern)
+	re, err := compilePattern(testCase.searchPattern, patternOptions{ignoreCase: true})
 	if err != nil {
 		t.Fatalf("Failed to compile regex pattern: %v", err)
 	}
 
-	matches, err := ParseEnvFile(testCase.filePath, re)
+	matches, err := ParsePropertiesFile(testCase.filePath, re)
 	if err != nil {
-		t.Fatalf("ParseEnvFile returned an error: %v", err)
+		t.Fatalf("ParsePropertiesFile returned an error: %v", err)
 	}
 
 	if len(matches) != len(testCase.expectedMatches) {
@@ -196,7 +369,7 @@ func TestParseEnvFile(t *testing.T) {
 		},
 	}
 
-	re, err := generateRegex(testCase.searchPattern)
+	re, err := compilePattern(testCase.searchPattern, patternOptions{ignoreCase: true})
 	if err != nil {
 		t.Fatalf("Failed to compile regex pattern: %v", err)
 	}
@@ -219,6 +392,47 @@ func TestParseEnvFile(t *testing.T) {
 	}
 }
 
+func TestParseDotenvSyntax(t *testing.T) {
+	path := abs("./testdata/unit/fixtures/.env.spec")
+	testCase := testCaseParseFile{
+		filePath:      path,
+		searchPattern: "app_",
+		expectedMatches: []Match{
+			{Path: path, LineNum: 2, Key: "APP_NAME", Value: "varip"},
+			{Path: path, LineNum: 3, Key: "APP_QUOTED", Value: "hello world"},
+			{Path: path, LineNum: 4, Key: "APP_SINGLE", Value: "single #not a comment"},
+			{Path: path, LineNum: 5, Key: "APP_COMMENT", Value: "value"},
+			{Path: path, LineNum: 6, Key: "APP_HASH", Value: "abc#123"},
+			{Path: path, LineNum: 7, Key: "APP_ESCAPES", Value: "line1\nline2 \"quoted\""},
+			{Path: path, LineNum: 8, Key: "APP_LITERAL", Value: `no\nescapes`},
+			{Path: path, LineNum: 9, Key: "APP_PRIVATE_KEY", Value: "-----BEGIN KEY-----\nMIIBOgIBAAJBAK\n-----END KEY-----"},
+			{Path: path, LineNum: 12, Key: "APP_BACKTICK", Value: `it's "both"`},
+			{Path: path, LineNum: 13, Key: "APP_EMPTY", Value: ""},
+			{Path: path, LineNum: 14, Key: "APP_INDENTED", Value: "spaced"},
+		},
+	}
+
+	re, err := compilePattern(testCase.searchPattern, patternOptions{ignoreCase: true})
+	if err != nil {
+		t.Fatalf("Failed to compile regex pattern: %v", err)
+	}
+
+	matches, err := ParseEnvFile(testCase.filePath, re)
+	if err != nil {
+		t.Fatalf("ParseEnvFile returned an error: %v", err)
+	}
+
+	if len(matches) != len(testCase.expectedMatches) {
+		t.Fatalf("Expected %d matches, got %d: %v", len(testCase.expectedMatches), len(matches), matches)
+	}
+
+	for i, match := range matches {
+		if match != testCase.expectedMatches[i] {
+			t.Errorf("Expected match %#v, got %#v at index %d", testCase.expectedMatches[i], match, i)
+		}
+	}
+}
+
 func contains(matches []Match, match Match) bool {
 	for _, m := range matches {
 		if m == match {
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ParsePropertiesFile parses a Java properties file and returns all matches.
+// Follows the java.util.Properties load rules: keys are separated from values by '=', ':' or whitespace,
+// lines ending in a backslash are continued, escapes are decoded, and '#' or '!' start a comment line.
+// Each match is reported on the line its logical entry starts.
+// Parses .properties
+func ParsePropertiesFile(filePath string, re *regexp.Regexp) ([]Match, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	lineNum := 0
+
+	var matches []Match
+
+	var logical strings.Builder
+	startLine := 0
+	continued := false
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		lineNum++
+
+		// Leading whitespace is ignored, on continuation lines as well
+		line = strings.TrimLeft(line, " \t\f")
+
+		if !continued {
+			if line == "" || line[0] == '#' || line[0] == '!' {
+				continue
+			}
+			startLine = lineNum
+		}
+
+		// An odd number of trailing backslashes continues the line
+		trailing := len(line) - len(strings.TrimRight(line, `\`))
+		continued = trailing%2 == 1
+		if continued {
+			line = line[:len(line)-1]
+		}
+		logical.WriteString(line)
+		if continued {
+			continue
+		}
+
+		key, value := splitPropertiesEntry(logical.String())
+		logical.Reset()
+
+		if re.MatchString(key) {
+			matches = append(matches, Match{Path: filePath, LineNum: startLine, Key: key, Value: value})
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	// A continuation on the last line still ends the entry
+	if continued && logical.Len() > 0 {
+		key, value := splitPropertiesEntry(logical.String())
+		if re.MatchString(key) {
+			matches = append(matches, Match{Path: filePath, LineNum: startLine, Key: key, Value: value})
+		}
+	}
+
+	return matches, nil
+}
+
+// splitPropertiesEntry splits a logical line into its unescaped key and value.
+// The key ends at the first unescaped '=', ':' or whitespace, which may be followed by more whitespace and one '=' or ':'.
+func splitPropertiesEntry(line string) (string, string) {
+	end := len(line)
+	for i := 0; i < len(line); i++ {
+		if line[i] == '\\' {
+			i++
+			continue
+		}
+		if strings.IndexByte("=: \t\f", line[i]) >= 0 {
+			end = i
+			break
+		}
+	}
+
+	key := line[:end]
+	rest := strings.TrimLeft(line[end:], " \t\f")
+	if rest != "" && (rest[0] == '=' || rest[0] == ':') {
+		rest = strings.TrimLeft(rest[1:], " \t\f")
+	}
+
+	return unescapeProperties(key), unescapeProperties(rest)
+}
+
+// unescapeProperties decodes \t, \n, \r, \f and \uXXXX escapes, any other escaped character stands for itself.
+func unescapeProperties(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			sb.WriteByte(s[i])
+			continue
+		}
+
+		i++
+		switch s[i] {
+		case 't':
+			sb.WriteByte('\t')
+		case 'n':
+			sb.WriteByte('\n')
+		case 'r':
+			sb.WriteByte('\r')
+		case 'f':
+			sb.WriteByte('\f')
+		case 'u':
+			if i+5 <= len(s) {
+				if code, err := strconv.ParseUint(s[i+1:i+5], 16, 32); err == nil {
+					sb.WriteRune(rune(code))
+					i += 4
+					continue
+				}
+			}
+			sb.WriteByte('u')
+		default:
+			sb.WriteByte(s[i])
+		}
+	}
+	return sb.String()
+}
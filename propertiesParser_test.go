@@ -0,0 +1,44 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestParseSpecPropertiesFile(t *testing.T) {
+	path := abs("./testdata/unit/fixtures/spec.properties")
+	testCase := testCaseParseFile{
+		filePath:      path,
+		searchPattern: "app.",
+		expectedMatches: []Match{
+			{Path: path, LineNum: 4, Key: "app.name", Value: "varip"},
+			{Path: path, LineNum: 5, Key: "app.colon", Value: "value"},
+			{Path: path, LineNum: 6, Key: "app.space", Value: "value with spaces"},
+			{Path: path, LineNum: 7, Key: "app.path", Value: `C:\configs\app`},
+			{Path: path, LineNum: 8, Key: "app.key=with escapes", Value: "escaped"},
+			{Path: path, LineNum: 9, Key: "app.greeting", Value: "Grüße"},
+			{Path: path, LineNum: 10, Key: "app.servers", Value: "alpha.example.com, beta.example.com, gamma.example.com"},
+			{Path: path, LineNum: 13, Key: "app.trailing", Value: `ends with a backslash\`},
+			{Path: path, LineNum: 14, Key: "app.empty", Value: ""},
+		},
+	}
+
+	re, err := compilePattern(testCase.searchPattern, patternOptions{ignoreCase: true})
+	if err != nil {
+		t.Fatalf("Failed to compile regex pattern: %v", err)
+	}
+
+	matches, err := ParsePropertiesFile(testCase.filePath, re)
+	if err != nil {
+		t.Fatalf("ParsePropertiesFile returned an error: %v", err)
+	}
+
+	if len(matches) != len(testCase.expectedMatches) {
+		t.Fatalf("Expected %d matches, got %d: %v", len(testCase.expectedMatches), len(matches), matches)
+	}
+
+	for i, match := range matches {
+		if match != testCase.expectedMatches[i] {
+			t.Errorf("Expected match %#v, got %#v at index %d", testCase.expectedMatches[i], match, i)
+		}
+	}
+}
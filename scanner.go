@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// scanner is the byte cursor shared by the TOML and HOCON parsers.
+// It keeps track of the current line, so entries and errors can point back at the source.
+type scanner struct {
+	src  string
+	pos  int
+	line int
+
+	// name prefixes error messages, e.g. "toml: line 3: ..."
+	name string
+	// blank holds the characters skipped by skipBlank, and comments the prefixes that start a line comment
+	blank    string
+	comments []string
+}
+
+func newScanner(name, src, blank string, comments ...string) scanner {
+	return scanner{src: src, line: 1, name: name, blank: blank, comments: comments}
+}
+
+// skipBlank skips blank characters, newlines and comments.
+func (s *scanner) skipBlank() {
+	for !s.eof() {
+		switch {
+		case strings.IndexByte(s.blank, s.peek()) >= 0:
+			s.advance()
+		case s.atComment():
+			s.skipLine()
+		default:
+			return
+		}
+	}
+}
+
+func (s *scanner) skipSpaces() {
+	for !s.eof() && (s.peek() == ' ' || s.peek() == '\t') {
+		s.pos++
+	}
+}
+
+// skipComment skips a line comment up to (but not including) the newline.
+func (s *scanner) skipComment() {
+	if s.atComment() {
+		s.skipLine()
+	}
+}
+
+func (s *scanner) atComment() bool {
+	for _, prefix := range s.comments {
+		if strings.HasPrefix(s.src[s.pos:], prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// skipLine skips to the end of the current line, not including the newline.
+func (s *scanner) skipLine() {
+	for !s.eof() && s.peek() != '\n' {
+		s.pos++
+	}
+}
+
+func (s *scanner) consume(c byte) bool {
+	if !s.eof() && s.peek() == c {
+		s.advance()
+		return true
+	}
+	return false
+}
+
+func (s *scanner) advance() {
+	if s.src[s.pos] == '\n' {
+		s.line++
+	}
+	s.pos++
+}
+
+func (s *scanner) peek() byte {
+	return s.src[s.pos]
+}
+
+func (s *scanner) eof() bool {
+	return s.pos >= len(s.src)
+}
+
+func (s *scanner) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%s: line %d: %s", s.name, s.line, fmt.Sprintf(format, args...))
+}
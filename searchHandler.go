@@ -17,11 +17,17 @@ func NewSearchHandler() *SearchHandler {
 	return &SearchHandler{}
 }
 
-func (handler *SearchHandler) Search(path string, pattern *regexp.Regexp, showHidden bool) error {
+func (handler *SearchHandler) Search(path string, matcher *Matcher, showHidden bool) error {
 	if !exists(path) {
 		return fmt.Errorf("file or directory %s does not exist", path)
 	}
 
+	root := path
+	searchRoot = path
+	if info, err := os.Stat(path); err == nil && !info.IsDir() {
+		searchRoot = filepath.Dir(path)
+	}
+
 	err := filepath.WalkDir(path, func(path string, d os.DirEntry, err error) error {
 		if err != nil {
 			handleError(err, path)
@@ -34,10 +40,12 @@ func (handler *SearchHandler) Search(path string, pattern *regexp.Regexp, showHi
 			return nil
 		}
 
-		if !showHidden {
+		// Only the part of the path below the search root is checked, so the directories the root itself is in
+		// (e.g. tmp or .github) don't hide everything, and an explicitly searched file or directory is never skipped
+		rel, relErr := filepath.Rel(root, path)
+		if !showHidden && relErr == nil && rel != "." {
 			// If the entry is a hidden file or directory, skip it
-			// Other than .env files, which are supported
-			if strings.HasPrefix(filepath.Base(path), ".") && !strings.Contains(d.Name(), ".env") {
+			if isHiddenEntry(rel, d.IsDir()) {
 				if d.IsDir() {
 					return filepath.SkipDir
 				}
@@ -46,7 +54,7 @@ func (handler *SearchHandler) Search(path string, pattern *regexp.Regexp, showHi
 
 			// If the entry is a directory, check if it's in the ignore list
 			for _, ignoredDir := range ignoredDirectories {
-				if strings.Contains(path, ignoredDir) {
+				if isInDirectory(rel, ignoredDir) {
 					if d.IsDir() {
 						return filepath.SkipDir
 					}
@@ -55,8 +63,8 @@ func (handler *SearchHandler) Search(path string, pattern *regexp.Regexp, showHi
 			}
 		}
 
-		if !d.IsDir() && isSupportedFileType(path) {
-			err := parseFile(path, pattern)
+		if !d.IsDir() && (scanUsages && isSourceFile(path) || isSupportedFileType(path)) {
+			err := parseFile(path, matcher)
 			if err != nil {
 				handleError(err, path)
 				verbose("Error searching in file %s: %s", path, err)
@@ -73,18 +81,47 @@ func (handler *SearchHandler) Search(path string, pattern *regexp.Regexp, showHi
 	return nil
 }
 
-// parseFile parses the file at the given path based on its file type and searches for the pattern.
-func parseFile(path string, pattern *regexp.Regexp) error {
+// parseFile parses the file at the given path based on its file type and prints the entries the matcher matches.
+// Parsers are given a pattern that matches every key, as the matcher can also test values.
+func parseFile(path string, matcher *Matcher) error {
+	pattern := matchAll
+
 	var results []Match
 	var err error
 
 	switch {
-	case strings.HasPrefix(filepath.Base(path), ".env") || strings.HasSuffix(path, ".properties"):
+	case scanUsages && isSourceFile(path):
+		results, err = ParseSourceFile(path, pattern)
+	case isDockerfile(path):
+		results, err = ParseDockerfile(path, pattern)
+	case isShellFile(path):
+		results, err = ParseShellFile(path, pattern)
+	case strings.HasPrefix(filepath.Base(path), ".env"):
 		results, err = ParseEnvFile(path, pattern)
-	case strings.HasSuffix(path, ".json"):
+	case strings.HasSuffix(path, ".properties"):
+		results, err = ParsePropertiesFile(path, pattern)
+	case strings.HasSuffix(path, ".json") || strings.HasSuffix(path, ".jsonc") || strings.HasSuffix(path, ".json5"):
 		results, err = ParseJSONFile(path, pattern)
+	case strings.HasSuffix(path, ".jsonl") || strings.HasSuffix(path, ".ndjson"):
+		results, err = ParseNDJSONFile(path, pattern)
+	case isCIFile(path):
+		results, err = ParseCIFile(path, pattern)
+	case isComposeFile(path):
+		results, err = ParseComposeFile(path, pattern)
 	case strings.HasSuffix(path, ".yml") || strings.HasSuffix(path, ".yaml"):
 		results, err = ParseYAMLFile(path, pattern)
+	case strings.HasSuffix(path, ".toml"):
+		results, err = ParseTOMLFile(path, pattern)
+	case isSystemdUnitFile(path):
+		results, err = ParseSystemdUnitFile(path, pattern)
+	case isHOCONFile(path):
+		results, err = ParseHOCONFile(path, pattern)
+	case strings.HasSuffix(path, ".ini") || strings.HasSuffix(path, ".cfg") || strings.HasSuffix(path, ".conf") || filepath.Base(path) == ".gitconfig":
+		results, err = ParseINIFile(path, pattern)
+	case strings.HasSuffix(path, ".xml") || strings.HasSuffix(path, ".config"):
+		results, err = ParseXMLFile(path, pattern)
+	case strings.HasSuffix(path, ".tf") || strings.HasSuffix(path, ".tfvars") || strings.HasSuffix(path, ".hcl"):
+		results, err = ParseHCLFile(path, pattern)
 
 	default:
 		err = fmt.Errorf("unsupported file type %s", path)
@@ -94,51 +131,151 @@ func parseFile(path string, pattern *regexp.Regexp) error {
 		return err
 	}
 
-	printMatches(results, pattern)
+	printMatches(matcher.Filter(results), matcher)
 
 	return nil
 }
 
 // printMatches pretty prints the matches found in the given list of Match objects.
-// Highlights the matched pattern in the key.
-func printMatches(m []Match, re *regexp.Regexp) {
+// Highlights the matched pattern in the key and value.
+func printMatches(m []Match, matcher *Matcher) {
 	if len(m) == 0 {
 		return
 	}
 
 	coloredPrintf(blue, "%s\n", m[0].Path)
 	highlight := color.New(color.FgHiRed).SprintFunc()
+	keyPatterns, valuePatterns := matcher.highlights()
 
 	for _, match := range m {
+		position := fmt.Sprintf("%d", match.LineNum)
+		if match.Column > 0 {
+			position = fmt.Sprintf("%d:%d", match.LineNum, match.Column)
+		}
+
 		y := color.New(color.Faint).SprintFunc()
-		highlightedLineNum := y(match.LineNum)
+		highlightedLineNum := y(position)
 
-		highlightedKey := re.ReplaceAllStringFunc(match.Key, func(s string) string {
-			return highlight(s)
-		})
+		highlightedKey := highlightMatches(match.Key, keyPatterns, highlight, fmt.Sprint)
 
 		f := color.New(color.Faint).SprintFunc()
-		highlightedValue := f(match.Value)
+		highlightedValue := highlightMatches(match.Value, valuePatterns, highlight, f)
+
+		scope := ""
+		if match.Usage {
+			scope = fmt.Sprintf("[usage, %s] ", match.Scope)
+		} else if match.Scope != "" {
+			scope = fmt.Sprintf("[%s] ", match.Scope)
+		}
 
 		if showColor {
-			// Line number is not supported for json parsing
+			// Some parsers (or matches) have no line number to report
 			if match.LineNum == 0 {
-				color.White("%s => %s", highlightedKey, highlightedValue)
+				color.White("%s%s => %s", f(scope), highlightedKey, highlightedValue)
 				continue
 			}
-			color.White("%s: %s => %s", highlightedLineNum, highlightedKey, highlightedValue)
+			color.White("%s: %s%s => %s", highlightedLineNum, f(scope), highlightedKey, highlightedValue)
 		} else {
 			if match.LineNum == 0 {
-				fmt.Printf("%s => %s\n", match.Key, match.Value)
+				fmt.Printf("%s%s => %s\n", scope, match.Key, match.Value)
 				continue
 			}
-			fmt.Printf("%d: %s => %s\n", match.LineNum, match.Key, match.Value)
+			fmt.Printf("%s: %s%s => %s\n", position, scope, match.Key, match.Value)
 		}
 	}
 
 	fmt.Println()
 }
 
+// resolveReference resolves a file referenced by the file at filePath (e.g. an env_file), relative to its directory.
+// It reports whether the referenced file is inside the search root (or the directory of filePath, if there is none),
+// so files on the machine running varip, such as /etc/default/app, are never reported as part of the searched files.
+func resolveReference(filePath, reference string) (string, bool) {
+	path := reference
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(filepath.Dir(filePath), path)
+	}
+
+	root := searchRoot
+	if root == "" {
+		root = filepath.Dir(filePath)
+	}
+
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path, false
+	}
+	return path, true
+}
+
+// isHiddenEntry reports whether the entry at path, relative to the search root, is hidden, and is skipped unless hidden
+// files are shown.
+// Entries whose name starts with a dot are hidden, other than .env files, .gitconfig, .devcontainer.json, CI configuration
+// and shell startup files (e.g. .bashrc or .zshrc), which are supported.
+// Everything in the .github directory other than the workflows is hidden too, and so is everything in the .vscode and
+// .devcontainer directories other than their JSONC files.
+func isHiddenEntry(path string, isDir bool) bool {
+	if isInDirectory(path, ".github") {
+		return !isCIPath(path, isDir)
+	}
+	for _, editorDir := range editorConfigDirectories {
+		if isInDirectory(path, editorDir) {
+			return !isEditorConfigPath(path, isDir)
+		}
+	}
+
+	name := filepath.Base(path)
+	if !isDir && (name == ".gitconfig" || name == ".devcontainer.json" || isShellFile(path)) {
+		return false
+	}
+	return strings.HasPrefix(name, ".") && !strings.Contains(name, ".env") && !isCIPath(path, isDir)
+}
+
+// isInDirectory reports whether any element of the given path is the named directory.
+// Elements are compared whole, so .github is not mistaken for .git
+func isInDirectory(path, name string) bool {
+	for _, element := range strings.Split(filepath.ToSlash(path), "/") {
+		if element == name {
+			return true
+		}
+	}
+	return false
+}
+
+// highlightMatches highlights every match of the given patterns in s, formatting the rest of s with plain.
+// Where a pattern has a highlight group (e.g. for whole segment matching), only that part of the match is highlighted.
+func highlightMatches(s string, patterns []*regexp.Regexp, highlight, plain func(a ...interface{}) string) string {
+	highlighted := make([]bool, len(s))
+	for _, re := range patterns {
+		group := re.SubexpIndex(highlightGroup)
+		for _, loc := range re.FindAllStringSubmatchIndex(s, -1) {
+			start, end := loc[0], loc[1]
+			if group > 0 && loc[2*group] >= 0 {
+				start, end = loc[2*group], loc[2*group+1]
+			}
+			for i := start; i < end; i++ {
+				highlighted[i] = true
+			}
+		}
+	}
+
+	var sb strings.Builder
+	for start := 0; start < len(s); {
+		end := start
+		for end < len(s) && highlighted[end] == highlighted[start] {
+			end++
+		}
+		if highlighted[start] {
+			sb.WriteString(highlight(s[start:end]))
+		} else {
+			sb.WriteString(plain(s[start:end]))
+		}
+		start = end
+	}
+
+	return sb.String()
+}
+
 func exists(path string) bool {
 	_, err := os.Stat(path)
 	if os.IsNotExist(err) {
@@ -2,18 +2,23 @@ package main
 
 import (
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
 	"testing"
 )
 
 func TestSearchHandlerInvalidFile(t *testing.T) {
 	h := NewSearchHandler()
 
-	pat, err := generateRegex("pattern")
+	pat, err := compilePattern("pattern", patternOptions{ignoreCase: true})
 	if err != nil {
 		t.Fatalf("Failed to compile regex pattern: %v", err)
 	}
 
-	err = h.Search("/path/to/invalidFile", pat, false)
+	err = h.Search("/path/to/invalidFile", NewMatcher(pat, nil), false)
 
 	if err == nil {
 		t.Errorf("Expected error, got nil")
@@ -21,3 +26,193 @@ func TestSearchHandlerInvalidFile(t *testing.T) {
 
 	fmt.Print(err)
 }
+
+func TestIsInDirectory(t *testing.T) {
+	if !isInDirectory("project/node_modules/pkg/.env", "node_modules") {
+		t.Errorf("Expected project/node_modules/pkg/.env to be in node_modules")
+	}
+	if isInDirectory("project/.github/workflows/deploy.yml", ".git") {
+		t.Errorf("Expected project/.github/workflows/deploy.yml not to be in .git")
+	}
+}
+
+func TestHighlightMatches(t *testing.T) {
+	highlight := func(a ...interface{}) string {
+		return fmt.Sprintf("<%s>", a...)
+	}
+
+	re, err := compilePattern("url", patternOptions{wholeSegment: true})
+	if err != nil {
+		t.Fatalf("Failed to compile pattern: %v", err)
+	}
+
+	expected := "spring.datasource.<url>"
+	if highlighted := highlightMatches("spring.datasource.url", []*regexp.Regexp{re}, highlight, fmt.Sprint); highlighted != expected {
+		t.Errorf("Expected %s, got %s", expected, highlighted)
+	}
+}
+
+func TestHighlightMatchesInValue(t *testing.T) {
+	highlight := func(a ...interface{}) string {
+		return fmt.Sprintf("<%s>", a...)
+	}
+	plain := func(a ...interface{}) string {
+		return fmt.Sprintf("(%s)", a...)
+	}
+
+	re, err := compilePattern("redis.host", patternOptions{ignoreCase: true})
+	if err != nil {
+		t.Fatalf("Failed to compile pattern: %v", err)
+	}
+
+	expected := "(redis://)<redis.host>(.com:6379)"
+	if highlighted := highlightMatches("redis://redis.host.com:6379", []*regexp.Regexp{re}, highlight, plain); highlighted != expected {
+		t.Errorf("Expected %s, got %s", expected, highlighted)
+	}
+
+	expected = "(redis://redis.host.com:6379)"
+	if highlighted := highlightMatches("redis://redis.host.com:6379", nil, highlight, plain); highlighted != expected {
+		t.Errorf("Expected %s, got %s", expected, highlighted)
+	}
+}
+
+func TestIsHiddenEntry(t *testing.T) {
+	testCases := []struct {
+		path   string
+		isDir  bool
+		hidden bool
+	}{
+		{path: "project/.git", isDir: true, hidden: true},
+		{path: "project/.env.local", hidden: false},
+		{path: "project/.github", isDir: true, hidden: false},
+		{path: "project/.github/workflows", isDir: true, hidden: false},
+		{path: "project/.github/workflows/deploy.yml", hidden: false},
+		{path: "project/.github/dependabot.yml", hidden: true},
+		{path: "project/.github/ISSUE_TEMPLATE", isDir: true, hidden: true},
+		{path: "project/.gitlab-ci.yml", hidden: false},
+		{path: "project/.vscode", isDir: true, hidden: false},
+		{path: "project/.vscode/launch.json", hidden: false},
+		{path: "project/.vscode/notes.txt", hidden: true},
+		{path: "project/.vscode/cache", isDir: true, hidden: true},
+		{path: "project/.devcontainer", isDir: true, hidden: false},
+		{path: "project/.devcontainer/devcontainer.json", hidden: false},
+		{path: "project/.devcontainer.json", hidden: false},
+		{path: "project/.idea", isDir: true, hidden: true},
+		{path: "home/.bashrc", hidden: false},
+		{path: "home/.zshrc", hidden: false},
+		{path: "project/.envrc", hidden: false},
+		{path: "home/.bashrc", isDir: true, hidden: true},
+		{path: "home/.gitconfig", hidden: false},
+		{path: "project/config/app.yml", hidden: false},
+	}
+
+	for _, testCase := range testCases {
+		if hidden := isHiddenEntry(testCase.path, testCase.isDir); hidden != testCase.hidden {
+			t.Errorf("Expected isHiddenEntry(%s) to be %v, got %v", testCase.path, testCase.hidden, hidden)
+		}
+	}
+}
+
+func TestSearchHandlerUsages(t *testing.T) {
+	defer func(usages, colored bool, root string) {
+		scanUsages, showColor, searchRoot = usages, colored, root
+	}(scanUsages, showColor, searchRoot)
+	showColor = false
+
+	re, err := compilePattern("db_host", patternOptions{ignoreCase: true})
+	if err != nil {
+		t.Fatalf("Failed to compile regex pattern: %v", err)
+	}
+	matcher := NewMatcher(re, nil)
+
+	// Search a copy of the fixture, so the result doesn't depend on where the repo is checked out
+	dir := t.TempDir()
+	goFile := filepath.Join(dir, "main.go")
+	source, err := os.ReadFile(abs("./testdata/unit/fixtures/usages/main.go"))
+	if err != nil {
+		t.Fatalf("Failed to read fixture: %v", err)
+	}
+	if err := os.WriteFile(goFile, source, 0o644); err != nil {
+		t.Fatalf("Failed to write %s: %v", goFile, err)
+	}
+
+	scanUsages = true
+	output := captureStdout(t, func() {
+		if err := NewSearchHandler().Search(dir, matcher, false); err != nil {
+			t.Fatalf("Search returned an error: %v", err)
+		}
+	})
+	if !strings.Contains(output, goFile+"\n6:21: [usage, os.Getenv] DB_HOST => host := os.Getenv(\"DB_HOST\")") {
+		t.Errorf("Expected the usage in %s to be reported with --usages, got:\n%s", goFile, output)
+	}
+
+	scanUsages = false
+	output = captureStdout(t, func() {
+		if err := NewSearchHandler().Search(dir, matcher, false); err != nil {
+			t.Fatalf("Search returned an error: %v", err)
+		}
+	})
+	if strings.Contains(output, goFile) {
+		t.Errorf("Expected %s to be ignored without --usages, got:\n%s", goFile, output)
+	}
+}
+
+func TestSearchHandlerRootInIgnoredDirectory(t *testing.T) {
+	defer func(colored bool, root string) { showColor, searchRoot = colored, root }(showColor, searchRoot)
+	showColor = false
+
+	dir := t.TempDir()
+	files := []string{
+		filepath.Join(dir, "build", "project", ".env"),
+		filepath.Join(dir, "repo", ".github", "actions", "config.yml"),
+	}
+	for _, file := range files {
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(file, []byte("DB_HOST: localhost\n"), 0o644); err != nil {
+			t.Fatalf("Failed to write %s: %v", file, err)
+		}
+	}
+
+	re, err := compilePattern("db_host", patternOptions{ignoreCase: true})
+	if err != nil {
+		t.Fatalf("Failed to compile regex pattern: %v", err)
+	}
+
+	// The ignored and hidden directories the search root is in don't count, only those below it
+	for _, file := range files {
+		root := filepath.Dir(file)
+		output := captureStdout(t, func() {
+			if err := NewSearchHandler().Search(root, NewMatcher(re, nil), false); err != nil {
+				t.Fatalf("Search returned an error: %v", err)
+			}
+		})
+		if !strings.Contains(output, file) {
+			t.Errorf("Expected %s to be searched from %s, got:\n%s", file, root, output)
+		}
+	}
+}
+
+// captureStdout returns everything fn prints to stdout.
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	output := make(chan string)
+	go func() {
+		b, _ := io.ReadAll(r)
+		output <- string(b)
+	}()
+
+	fn()
+	w.Close()
+	return <-output
+}
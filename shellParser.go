@@ -0,0 +1,336 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// shellVariableName matches a valid shell variable name.
+var shellVariableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// shellHeredoc matches a heredoc operator and its delimiter, capturing the delimiter.
+var shellHeredoc = regexp.MustCompile(`^<<-?[ \t]*["'\\]?([A-Za-z_][A-Za-z0-9_]*)["']?`)
+
+// shellDeclarations are the builtins that can assign variables, e.g. export FOO=bar or declare -x FOO=bar.
+var shellDeclarations = map[string]bool{"export": true, "declare": true, "typeset": true, "readonly": true, "local": true}
+
+// shellKeywords can precede an assignment within a statement, e.g. if ...; then FOO=bar; fi
+var shellKeywords = map[string]bool{"then": true, "else": true, "do": true, "{": true, "(": true, "!": true}
+
+// ParseShellFile parses a shell script and returns all matches for variable assignments.
+// Handles plain assignments (FOO=bar), assignments through builtins (export FOO=bar, declare -x FOO=bar)
+// and quoted values. Everything else, including the contents of heredocs, is ignored.
+// Parses .sh, .bash, .zsh, .envrc, .bashrc, .profile
+func ParseShellFile(filePath string, re *regexp.Regexp) ([]Match, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	lineNum := 0
+
+	var matches []Match
+
+	var logical strings.Builder
+	startLine := 0
+	heredoc := ""
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		lineNum++
+
+		if heredoc != "" {
+			if strings.TrimSpace(line) == heredoc {
+				heredoc = ""
+			}
+			continue
+		}
+
+		if logical.Len() == 0 {
+			startLine = lineNum
+		}
+
+		// A trailing (unescaped) backslash continues the line
+		if strings.HasSuffix(line, `\`) && !strings.HasSuffix(line, `\\`) {
+			logical.WriteString(strings.TrimSuffix(line, `\`))
+			continue
+		}
+		logical.WriteString(line)
+		text := stripShellComment(logical.String())
+
+		// So does a quote that is still open, e.g. a multi-line certificate
+		if shellQuoteOpen(text) {
+			logical.WriteString("\n")
+			continue
+		}
+		logical.Reset()
+
+		heredoc = shellHeredocDelimiter(text)
+		matches = append(matches, shellMatches(filePath, text, startLine, re)...)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	// An unterminated quote runs to the end of the file
+	if logical.Len() > 0 {
+		matches = append(matches, shellMatches(filePath, strings.TrimSuffix(logical.String(), "\n"), startLine, re)...)
+	}
+
+	return matches, nil
+}
+
+// shellMatches returns the matches for the variable assignments of a logical line, which starts on lineNum.
+func shellMatches(filePath, text string, lineNum int, re *regexp.Regexp) []Match {
+	var matches []Match
+	for _, statement := range splitShellStatements(text) {
+		for _, pair := range shellAssignments(statement) {
+			if re.MatchString(pair[0]) {
+				matches = append(matches, Match{Path: filePath, LineNum: lineNum, Key: pair[0], Value: pair[1]})
+			}
+		}
+	}
+	return matches
+}
+
+// shellAssignments returns the variable assignments of a single statement.
+func shellAssignments(statement string) [][2]string {
+	words := splitShellWords(statement, `\`)
+
+	i := 0
+	for i < len(words) && shellKeywords[words[i]] {
+		i++
+	}
+
+	declaration := i < len(words) && shellDeclarations[words[i]]
+	if declaration {
+		i++
+		for i < len(words) && strings.HasPrefix(words[i], "-") {
+			i++
+		}
+	}
+
+	var pairs [][2]string
+	for ; i < len(words); i++ {
+		key, value, found := strings.Cut(words[i], "=")
+		if !found || !shellVariableName.MatchString(key) {
+			if declaration {
+				// e.g. export FOO, which doesn't assign a value
+				continue
+			}
+			// Assignments only prefix a command, anything after the command name is an argument
+			break
+		}
+
+		// Array assignments, e.g. FOO=(a b c)
+		if strings.HasPrefix(value, "(") {
+			for !strings.HasSuffix(value, ")") && i+1 < len(words) {
+				i++
+				value += " " + words[i]
+			}
+		}
+
+		pairs = append(pairs, [2]string{key, value})
+	}
+
+	return pairs
+}
+
+// splitShellStatements splits a line into statements on unquoted ;, &&, || and | operators.
+func splitShellStatements(line string) []string {
+	var statements []string
+	start := 0
+
+	scanShellUnquoted(line, func(i int) int {
+		c := line[i]
+		if c != ';' && c != '&' && c != '|' {
+			return 0
+		}
+		statements = append(statements, line[start:i])
+		skip := 0
+		for i+skip+1 < len(line) && line[i+skip+1] == c {
+			skip++
+		}
+		start = i + skip + 1
+		return skip
+	})
+
+	return append(statements, line[start:])
+}
+
+// stripShellComment removes a comment, which starts with an unquoted # at the beginning of a word.
+func stripShellComment(line string) string {
+	end := len(line)
+	scanShellUnquoted(line, func(i int) int {
+		if line[i] == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t') {
+			end = i
+			return -1
+		}
+		return 0
+	})
+	return line[:end]
+}
+
+// shellHeredocDelimiter returns the delimiter of a heredoc started on the line, or "" if there is none.
+// Only an unquoted << outside arithmetic ($((...)) or ((...))) starts a heredoc, and a <<< here-string doesn't.
+func shellHeredocDelimiter(line string) string {
+	delimiter := ""
+	arithmetic := 0
+
+	scanShellUnquoted(line, func(i int) int {
+		rest := line[i:]
+		switch {
+		case strings.HasPrefix(rest, "(("):
+			arithmetic++
+			return 1
+		case arithmetic > 0 && strings.HasPrefix(rest, "))"):
+			arithmetic--
+			return 1
+		case arithmetic > 0:
+			return 0
+		case strings.HasPrefix(rest, "<<<"):
+			return 2
+		}
+		if groups := shellHeredoc.FindStringSubmatch(rest); groups != nil {
+			delimiter = groups[1]
+			return -1
+		}
+		return 0
+	})
+
+	return delimiter
+}
+
+// shellQuoteOpen reports whether line ends within a quoted string.
+func shellQuoteOpen(line string) bool {
+	return scanShellUnquoted(line, func(int) int { return 0 }) != 0
+}
+
+// scanShellUnquoted calls visit with the index of every character of line that is not quoted or escaped.
+// visit returns how many of the following characters to skip, or a negative number to stop scanning.
+// It returns the quote that is still open at the end of line, or 0 if there is none or visit stopped the scan.
+func scanShellUnquoted(line string, visit func(i int) int) byte {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case c == '\\' && quote != '\'':
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'' || c == '`':
+			quote = c
+		default:
+			skip := visit(i)
+			if skip < 0 {
+				return 0
+			}
+			i += skip
+		}
+	}
+	return quote
+}
+
+// splitShellWords splits s into words on unquoted whitespace, removing quotes and escape characters.
+// Command substitutions and parameter expansions ($(...), ${...} and backticks) are kept as written.
+func splitShellWords(s, escape string) []string {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	var quote rune
+
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		c := runes[i]
+		switch {
+		case string(c) == escape && quote != '\'' && i+1 < len(runes):
+			// Within double quotes only quotes, escapes and $ can be escaped
+			next := runes[i+1]
+			if quote == '"' && next != '"' && next != '$' && string(next) != escape {
+				word.WriteRune(c)
+				continue
+			}
+			word.WriteRune(next)
+			i++
+			inWord = true
+		case quote != '\'' && c == '$' && i+1 < len(runes) && (runes[i+1] == '(' || runes[i+1] == '{'):
+			end := matchingShellBracket(runes, i+1)
+			word.WriteString(string(runes[i : end+1]))
+			i = end
+			inWord = true
+		case quote == 0 && c == '`':
+			end := i + 1
+			for end < len(runes) && runes[end] != '`' {
+				end++
+			}
+			if end == len(runes) {
+				end--
+			}
+			word.WriteString(string(runes[i : end+1]))
+			i = end
+			inWord = true
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			} else {
+				word.WriteRune(c)
+			}
+		case c == '"' || c == '\'':
+			quote = c
+			inWord = true
+		case c == ' ' || c == '\t':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(c)
+			inWord = true
+		}
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+
+	return words
+}
+
+// matchingShellBracket returns the index of the bracket closing the one at start, or the last index if it is not closed.
+func matchingShellBracket(runes []rune, start int) int {
+	open, close := runes[start], ')'
+	if open == '{' {
+		close = '}'
+	}
+
+	depth := 0
+	for i := start; i < len(runes); i++ {
+		switch runes[i] {
+		case open:
+			depth++
+		case close:
+			if depth--; depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(runes) - 1
+}
+
+// isShellFile reports whether the given path is a shell script or shell startup file.
+func isShellFile(path string) bool {
+	for _, fileType := range shellFileTypes {
+		if match, _ := filepath.Match(fileType, filepath.Base(path)); match {
+			return true
+		}
+	}
+	return false
+}
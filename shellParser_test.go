@@ -0,0 +1,43 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestParseShellFile(t *testing.T) {
+	script := abs("./testdata/unit/fixtures/shell/setenv.sh")
+	envrc := abs("./testdata/unit/fixtures/shell/.envrc")
+	testCases := []testCaseParseFile{
+		{
+			filePath:      script,
+			searchPattern: "db_",
+			expectedMatches: []Match{
+				{Path: script, LineNum: 3, Key: "DB_HOST", Value: "localhost"},
+				{Path: script, LineNum: 4, Key: "DB_USER", Value: "app user"},
+				{Path: script, LineNum: 4, Key: "DB_PORT", Value: "5432"},
+				{Path: script, LineNum: 5, Key: "DB_PASSWORD", Value: "p@ss #not a comment"},
+				{Path: script, LineNum: 6, Key: "DB_NAME", Value: "app"},
+				{Path: script, LineNum: 7, Key: "DB_URL", Value: "postgres://${DB_USER}@${DB_HOST}:${DB_PORT}/${DB_NAME}"},
+				{Path: script, LineNum: 8, Key: "DB_STARTED", Value: "$(date +%s)"},
+				{Path: script, LineNum: 9, Key: "DB_REPLICAS", Value: "(alpha beta)"},
+				{Path: script, LineNum: 10, Key: "DB_SSL", Value: "disable"},
+				{Path: script, LineNum: 11, Key: "DB_POOL", Value: "10"},
+				// A << in quotes or arithmetic, or a <<< here-string, doesn't start a heredoc
+				{Path: script, LineNum: 20, Key: "DB_MASK", Value: "$((1 << DB_BITS))"},
+				{Path: script, LineNum: 21, Key: "DB_AFTER_QUOTED", Value: "ok"},
+				{Path: script, LineNum: 23, Key: "DB_LAST", Value: "yes"},
+				{Path: script, LineNum: 25, Key: "DB_CERT", Value: "-----BEGIN CERTIFICATE-----\nDB_INNER=abc\n-----END CERTIFICATE-----"},
+				{Path: script, LineNum: 28, Key: "DB_AFTER_CERT", Value: "ok"},
+			},
+		},
+		{
+			filePath:      envrc,
+			searchPattern: "api_key",
+			expectedMatches: []Match{
+				{Path: envrc, LineNum: 1, Key: "API_KEY", Value: "secret"},
+			},
+		},
+	}
+
+	assertParsedMatches(t, ParseShellFile, testCases)
+}
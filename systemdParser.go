@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ParseSystemdUnitFile parses a systemd unit file and returns all matches.
+// Directives are reported as section.key, except Environment= which is split into one match per variable.
+// The variables of EnvironmentFile= references are reported against the directive that loads them,
+// if the file is inside the searched directory and can be read.
+// Parses .service, and .conf drop-ins under .service.d
+func ParseSystemdUnitFile(filePath string, re *regexp.Regexp) ([]Match, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	lineNum := 0
+
+	var matches []Match
+	section := ""
+
+	var logical strings.Builder
+	startLine := 0
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		lineNum++
+
+		if logical.Len() == 0 {
+			startLine = lineNum
+			if line == "" || isINIComment(line) {
+				continue
+			}
+		} else if isINIComment(line) {
+			// Comments within a continued directive are ignored
+			continue
+		}
+
+		// A trailing backslash continues the directive, joined with a space
+		if strings.HasSuffix(line, `\`) {
+			logical.WriteString(strings.TrimSpace(strings.TrimSuffix(line, `\`)) + " ")
+			continue
+		}
+		logical.WriteString(line)
+		text := logical.String()
+		logical.Reset()
+
+		if strings.HasPrefix(text, "[") && strings.HasSuffix(text, "]") {
+			section = text[1 : len(text)-1]
+			continue
+		}
+
+		key, value, found := strings.Cut(text, "=")
+		if !found {
+			continue
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+
+		switch key {
+		case "Environment":
+			scope := fmt.Sprintf("%s Environment", section)
+			for _, word := range splitShellWords(value, `\`) {
+				name, v, found := strings.Cut(word, "=")
+				if found && re.MatchString(name) {
+					matches = append(matches, Match{Path: filePath, LineNum: startLine, Key: name, Value: v, Scope: scope})
+				}
+			}
+			continue
+		case "EnvironmentFile":
+			matches = append(matches, systemdEnvironmentFile(filePath, startLine, value, re)...)
+		}
+
+		if section != "" {
+			key = section + "." + key
+		}
+		if re.MatchString(key) {
+			matches = append(matches, Match{Path: filePath, LineNum: startLine, Key: key, Value: value})
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return matches, nil
+}
+
+// systemdEnvironmentFile returns the matches in the env file referenced by an EnvironmentFile= directive on the given line.
+// A leading - marks the file as optional, relative paths are resolved against the unit file.
+// Files outside the search root (e.g. /etc/default/app) are not followed, the directive itself is still reported.
+func systemdEnvironmentFile(filePath string, line int, reference string, re *regexp.Regexp) []Match {
+	envPath, ok := resolveReference(filePath, strings.TrimPrefix(reference, "-"))
+	if !ok {
+		verbose("Not following EnvironmentFile %s of %s, it is outside the searched directory", envPath, filePath)
+		return nil
+	}
+
+	envMatches, err := ParseEnvFile(envPath, re)
+	if err != nil {
+		verbose("Error reading EnvironmentFile %s of %s: %s", envPath, filePath, err)
+		return nil
+	}
+
+	var matches []Match
+	for _, envMatch := range envMatches {
+		matches = append(matches, Match{
+			Path:    filePath,
+			LineNum: line,
+			Key:     envMatch.Key,
+			Value:   envMatch.Value,
+			Scope:   fmt.Sprintf("EnvironmentFile %s:%d", reference, envMatch.LineNum),
+		})
+	}
+	return matches
+}
+
+// isSystemdUnitFile reports whether the given path is a systemd service unit, or a drop-in for one.
+func isSystemdUnitFile(path string) bool {
+	if strings.HasSuffix(path, ".service") {
+		return true
+	}
+	return strings.HasSuffix(path, ".conf") && strings.HasSuffix(filepath.Base(filepath.Dir(path)), ".service.d")
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseSystemdUnitFile(t *testing.T) {
+	unit := abs("./testdata/unit/fixtures/systemd/app.service")
+	dropIn := abs("./testdata/unit/fixtures/systemd/app.service.d/override.conf")
+	testCases := []testCaseParseFile{
+		{
+			filePath:      unit,
+			searchPattern: "app",
+			expectedMatches: []Match{
+				{Path: unit, LineNum: 6, Key: "APP_PORT", Value: "8080", Scope: "Service Environment"},
+				{Path: unit, LineNum: 6, Key: "APP_NAME", Value: "app server", Scope: "Service Environment"},
+				{Path: unit, LineNum: 7, Key: "APP_DEBUG", Value: "false", Scope: "Service Environment"},
+				{Path: unit, LineNum: 7, Key: "APP_LOG", Value: "/var/log/app.log", Scope: "Service Environment"},
+				{Path: unit, LineNum: 9, Key: "APP_SECRET", Value: "s3cret", Scope: "EnvironmentFile app.env:1"},
+			},
+		},
+		{
+			filePath:      unit,
+			searchPattern: "service.e",
+			expectedMatches: []Match{
+				{Path: unit, LineNum: 8, Key: "Service.EnvironmentFile", Value: "-/etc/default/varip-missing-app"},
+				{Path: unit, LineNum: 9, Key: "Service.EnvironmentFile", Value: "app.env"},
+				{Path: unit, LineNum: 10, Key: "Service.ExecStart", Value: "/usr/bin/app --port ${APP_PORT}"},
+			},
+		},
+		{
+			filePath:      dropIn,
+			searchPattern: "app_port",
+			expectedMatches: []Match{
+				{Path: dropIn, LineNum: 2, Key: "APP_PORT", Value: "9090", Scope: "Service Environment"},
+			},
+		},
+	}
+
+	assertParsedMatches(t, ParseSystemdUnitFile, testCases)
+}
+
+func TestParseSystemdUnitFileOutsideSearchRoot(t *testing.T) {
+	host := t.TempDir()
+	envPath := filepath.Join(host, "app")
+	if err := os.WriteFile(envPath, []byte("HOST_SECRET=leaked\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write env file: %v", err)
+	}
+
+	repo := t.TempDir()
+	unitPath := filepath.Join(repo, "app.service")
+	if err := os.WriteFile(unitPath, []byte("[Service]\nEnvironmentFile=-"+envPath+"\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write unit file: %v", err)
+	}
+
+	defer func(root string) { searchRoot = root }(searchRoot)
+	searchRoot = repo
+
+	matches, err := ParseSystemdUnitFile(unitPath, matchAll)
+	if err != nil {
+		t.Fatalf("ParseSystemdUnitFile returned an error: %v", err)
+	}
+
+	// Only the reference is reported, the env file outside the search root is not read
+	expected := Match{Path: unitPath, LineNum: 2, Key: "Service.EnvironmentFile", Value: "-" + envPath}
+	if len(matches) != 1 || matches[0] != expected {
+		t.Fatalf("Expected only %#v, got %v", expected, matches)
+	}
+}
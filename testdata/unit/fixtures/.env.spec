@@ -0,0 +1,15 @@
+# dotenv syntax
+export APP_NAME=varip
+APP_QUOTED="hello world" # trailing comment
+APP_SINGLE='single #not a comment'
+APP_COMMENT=value # comment
+APP_HASH=abc#123
+APP_ESCAPES="line1\nline2 \"quoted\""
+APP_LITERAL='no\nescapes'
+APP_PRIVATE_KEY="-----BEGIN KEY-----
+MIIBOgIBAAJBAK
+-----END KEY-----"
+APP_BACKTICK=`it's "both"`
+APP_EMPTY=
+  APP_INDENTED = spaced
+#APP_DISABLED=true
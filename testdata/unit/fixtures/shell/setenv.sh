@@ -0,0 +1,28 @@
+#!/usr/bin/env bash
+# Environment for local development
+export DB_HOST=localhost
+export DB_USER="app user" DB_PORT=5432
+declare -x DB_PASSWORD='p@ss #not a comment'
+DB_NAME=app # comment
+DB_URL="postgres://${DB_USER}@${DB_HOST}:${DB_PORT}/${DB_NAME}"
+DB_STARTED=$(date +%s)
+DB_REPLICAS=(alpha beta)
+if [ -z "$DB_SSL" ]; then DB_SSL=disable; fi
+export DB_POOL=\
+10
+echo "DB_IGNORED=true"
+run --db DB_ARG=1
+cat <<EOF > db.env
+DB_HEREDOC=ignored
+EOF
+export DB_HOST
+echo "DB_SHIFT << b"
+DB_MASK=$((1 << DB_BITS))
+DB_AFTER_QUOTED=ok
+cat <<< "DB_HERESTRING=ignored"
+DB_LAST=yes
+# the certificate's lines are a single value
+export DB_CERT="-----BEGIN CERTIFICATE-----
+DB_INNER=abc
+-----END CERTIFICATE-----"
+DB_AFTER_CERT=ok
@@ -0,0 +1,44 @@
+# Database configuration
+variable "db_password" {
+  description = "Password for the \"main\" database"
+  type        = string
+  default     = "${DB_PASSWORD}"
+  sensitive   = true
+}
+
+locals {
+  region = "eu-west-2"
+  tags = {
+    Environment = "dev"
+    Owner       = var.owner
+  }
+}
+
+provider "aws" {
+  region = local.region
+}
+
+resource "aws_db_instance" "main" {
+  username            = "admin"
+  password            = var.db_password
+  port                = 5432
+  allocated_storage   = var.large ? 100 : 20
+  vpc_security_groups = ["sg-123", aws_security_group.db.id]
+  /* block comment */
+  parameters = {
+    "max_connections" = "100"
+  }
+
+  lifecycle { ignore_changes = [password] }
+}
+
+resource "aws_instance" "web" {
+  user_data = <<-EOT
+    #!/bin/bash
+    echo "hello"
+  EOT
+}
+
+output "names" {
+  value = [for s in var.names : upper(s)]
+}
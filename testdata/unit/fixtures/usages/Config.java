@@ -0,0 +1,6 @@
+public class Config {
+    @Value("${spring.datasource.url:jdbc:h2:mem}")
+    private String url;
+
+    String host = System.getenv("DB_HOST");
+}
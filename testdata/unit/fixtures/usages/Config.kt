@@ -0,0 +1 @@
+class Config(@Value("\${spring.datasource.username}") val username: String)
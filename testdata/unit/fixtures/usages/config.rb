@@ -0,0 +1,2 @@
+host = ENV['DB_HOST']
+port = ENV.fetch("DB_PORT", 5432)
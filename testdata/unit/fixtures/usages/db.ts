@@ -0,0 +1,2 @@
+const host = process.env.DB_HOST ?? process.env["DB_FALLBACK_HOST"];
+const port = import.meta.env.VITE_DB_PORT;
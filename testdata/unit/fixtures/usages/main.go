@@ -0,0 +1,9 @@
+package main
+
+import "os"
+
+func main() {
+	host := os.Getenv("DB_HOST")
+	port, ok := os.LookupEnv("DB_PORT")
+	_ = os.Getenv(key)
+}
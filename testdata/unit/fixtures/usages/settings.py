@@ -0,0 +1,5 @@
+import os
+
+HOST = os.environ["DB_HOST"]
+PORT = os.environ.get('DB_PORT', 5432)
+NAME = os.getenv("DB_NAME")
@@ -0,0 +1,393 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ParseTOMLFile parses a TOML file, flattens it, and finds matches based on the given regexp.
+// Tables, arrays of tables and inline tables are flattened into dotted keys (e.g. tool.poetry.name).
+// Parses .toml
+func ParseTOMLFile(filePath string, re *regexp.Regexp) ([]Match, error) {
+	file, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &tomlParser{scanner: newScanner("toml", string(file), " \t\r\n", "#"), arrayTables: make(map[string]int)}
+	if err = p.parse(); err != nil {
+		return nil, err
+	}
+
+	var matches []Match
+	for _, entry := range p.entries {
+		if re.MatchString(entry.Key) {
+			entry.Path = filePath
+			matches = append(matches, entry)
+		}
+	}
+
+	return matches, nil
+}
+
+// tomlParser flattens a TOML document into key/value entries, table by table.
+// It only validates as much of the syntax as is needed to extract values and their line numbers.
+type tomlParser struct {
+	scanner
+
+	// prefix is the flattened key of the current table, including the trailing "."
+	prefix string
+	// arrayTables counts the elements seen so far for each (flattened) array of tables
+	arrayTables map[string]int
+
+	entries []Match
+}
+
+func (p *tomlParser) parse() error {
+	for {
+		p.skipBlank()
+		if p.eof() {
+			return nil
+		}
+
+		var err error
+		if p.peek() == '[' {
+			err = p.parseTableHeader()
+		} else {
+			err = p.parseKeyValue(p.prefix)
+		}
+		if err != nil {
+			return err
+		}
+
+		p.skipSpaces()
+		p.skipComment()
+		if !p.eof() && p.peek() != '\n' && p.peek() != '\r' {
+			return p.errorf("expected newline, found %q", p.peek())
+		}
+	}
+}
+
+// parseTableHeader parses a [table] or [[array.of.tables]] header and updates the current prefix.
+func (p *tomlParser) parseTableHeader() error {
+	p.pos++
+	isArray := p.consume('[')
+
+	p.skipSpaces()
+	keys, err := p.parseKey()
+	if err != nil {
+		return err
+	}
+	p.skipSpaces()
+
+	if !p.consume(']') || (isArray && !p.consume(']')) {
+		return p.errorf("unterminated table header")
+	}
+
+	if !isArray {
+		p.prefix = p.resolve(keys) + "."
+		return nil
+	}
+
+	full := keys[len(keys)-1]
+	if len(keys) > 1 {
+		full = p.resolve(keys[:len(keys)-1]) + "." + full
+	}
+	index := p.arrayTables[full]
+	p.arrayTables[full]++
+	p.prefix = fmt.Sprintf("%s.[%d].", full, index)
+
+	return nil
+}
+
+// resolve joins the given header keys, pointing any array of tables in the path at its latest element.
+func (p *tomlParser) resolve(keys []string) string {
+	path := ""
+	for i, key := range keys {
+		if i > 0 {
+			path += "."
+		}
+		path += key
+		if count, ok := p.arrayTables[path]; ok {
+			path += fmt.Sprintf(".[%d]", count-1)
+		}
+	}
+	return path
+}
+
+func (p *tomlParser) parseKeyValue(prefix string) error {
+	line := p.line
+	keys, err := p.parseKey()
+	if err != nil {
+		return err
+	}
+
+	p.skipSpaces()
+	if !p.consume('=') {
+		return p.errorf("expected '=' after key %s", strings.Join(keys, "."))
+	}
+	p.skipSpaces()
+
+	return p.parseValue(prefix+strings.Join(keys, "."), line)
+}
+
+// parseKey parses a bare, quoted or dotted key into its parts.
+func (p *tomlParser) parseKey() ([]string, error) {
+	var keys []string
+	for {
+		var key string
+		switch {
+		case p.eof():
+			return nil, p.errorf("unexpected end of file, expected key")
+		case p.peek() == '"':
+			s, err := p.parseBasicString()
+			if err != nil {
+				return nil, err
+			}
+			key = s
+		case p.peek() == '\'':
+			s, err := p.parseLiteralString()
+			if err != nil {
+				return nil, err
+			}
+			key = s
+		default:
+			start := p.pos
+			for !p.eof() && isTOMLBareKeyChar(p.peek()) {
+				p.pos++
+			}
+			if start == p.pos {
+				return nil, p.errorf("invalid key character %q", p.peek())
+			}
+			key = p.src[start:p.pos]
+		}
+		keys = append(keys, key)
+
+		p.skipSpaces()
+		if !p.consume('.') {
+			return keys, nil
+		}
+		p.skipSpaces()
+	}
+}
+
+func (p *tomlParser) parseValue(key string, line int) error {
+	if p.eof() {
+		return p.errorf("missing value for key %s", key)
+	}
+
+	switch {
+	case strings.HasPrefix(p.src[p.pos:], `"""`):
+		s, err := p.parseMultilineString(`"""`, true)
+		if err != nil {
+			return err
+		}
+		p.add(key, s, line)
+	case strings.HasPrefix(p.src[p.pos:], `'''`):
+		s, err := p.parseMultilineString(`'''`, false)
+		if err != nil {
+			return err
+		}
+		p.add(key, s, line)
+	case p.peek() == '"':
+		s, err := p.parseBasicString()
+		if err != nil {
+			return err
+		}
+		p.add(key, s, line)
+	case p.peek() == '\'':
+		s, err := p.parseLiteralString()
+		if err != nil {
+			return err
+		}
+		p.add(key, s, line)
+	case p.peek() == '[':
+		return p.parseArray(key)
+	case p.peek() == '{':
+		return p.parseInlineTable(key)
+	default:
+		start := p.pos
+		for !p.eof() && !strings.ContainsRune(",]}#\r\n", rune(p.peek())) {
+			p.pos++
+		}
+		value := strings.TrimSpace(p.src[start:p.pos])
+		if value == "" {
+			return p.errorf("missing value for key %s", key)
+		}
+		p.add(key, value, line)
+	}
+
+	return nil
+}
+
+func (p *tomlParser) parseArray(key string) error {
+	p.pos++
+	for i := 0; ; i++ {
+		p.skipBlank()
+		if p.consume(']') {
+			return nil
+		}
+
+		if err := p.parseValue(fmt.Sprintf("%s.[%d]", key, i), p.line); err != nil {
+			return err
+		}
+
+		p.skipBlank()
+		if p.consume(']') {
+			return nil
+		}
+		if !p.consume(',') {
+			return p.errorf("expected ',' or ']' in array %s", key)
+		}
+	}
+}
+
+func (p *tomlParser) parseInlineTable(key string) error {
+	p.pos++
+	for {
+		p.skipBlank()
+		if p.consume('}') {
+			return nil
+		}
+
+		if err := p.parseKeyValue(key + "."); err != nil {
+			return err
+		}
+
+		p.skipBlank()
+		if p.consume('}') {
+			return nil
+		}
+		if !p.consume(',') {
+			return p.errorf("expected ',' or '}' in inline table %s", key)
+		}
+	}
+}
+
+func (p *tomlParser) parseBasicString() (string, error) {
+	p.pos++
+	var sb strings.Builder
+	for {
+		if p.eof() || p.peek() == '\n' {
+			return "", p.errorf("unterminated string")
+		}
+		c := p.src[p.pos]
+		p.pos++
+		switch c {
+		case '"':
+			return sb.String(), nil
+		case '\\':
+			if err := p.parseEscape(&sb); err != nil {
+				return "", err
+			}
+		default:
+			sb.WriteByte(c)
+		}
+	}
+}
+
+func (p *tomlParser) parseLiteralString() (string, error) {
+	p.pos++
+	end := strings.IndexAny(p.src[p.pos:], "'\n")
+	if end < 0 || p.src[p.pos+end] != '\'' {
+		return "", p.errorf("unterminated string")
+	}
+	s := p.src[p.pos : p.pos+end]
+	p.pos += end + 1
+	return s, nil
+}
+
+// parseMultilineString parses a multi-line basic or literal string, delimited by the given quotes.
+func (p *tomlParser) parseMultilineString(delim string, escapes bool) (string, error) {
+	p.pos += len(delim)
+	// A newline immediately following the opening delimiter is trimmed
+	p.consume('\r')
+	p.consume('\n')
+
+	var sb strings.Builder
+	for {
+		if p.eof() {
+			return "", p.errorf("unterminated multi-line string")
+		}
+		if strings.HasPrefix(p.src[p.pos:], delim) {
+			// Up to two quotes are allowed directly before the closing delimiter
+			for strings.HasPrefix(p.src[p.pos+1:], delim) {
+				sb.WriteByte(p.src[p.pos])
+				p.pos++
+			}
+			p.pos += len(delim)
+			return sb.String(), nil
+		}
+
+		c := p.src[p.pos]
+		p.advance()
+		if c == '\\' && escapes {
+			// A line ending backslash trims all whitespace up to the next non-whitespace character
+			rest := strings.TrimLeft(p.src[p.pos:], " \t")
+			if strings.HasPrefix(rest, "\n") || strings.HasPrefix(rest, "\r\n") {
+				for !p.eof() && strings.ContainsRune(" \t\r\n", rune(p.peek())) {
+					p.advance()
+				}
+				continue
+			}
+			if err := p.parseEscape(&sb); err != nil {
+				return "", err
+			}
+			continue
+		}
+		sb.WriteByte(c)
+	}
+}
+
+// parseEscape decodes the escape sequence following a backslash.
+func (p *tomlParser) parseEscape(sb *strings.Builder) error {
+	if p.eof() {
+		return p.errorf("unterminated escape sequence")
+	}
+	c := p.src[p.pos]
+	p.pos++
+	switch c {
+	case 'b':
+		sb.WriteByte('\b')
+	case 't':
+		sb.WriteByte('\t')
+	case 'n':
+		sb.WriteByte('\n')
+	case 'f':
+		sb.WriteByte('\f')
+	case 'r':
+		sb.WriteByte('\r')
+	case 'e':
+		sb.WriteByte('\x1b')
+	case '"', '\\':
+		sb.WriteByte(c)
+	case 'u', 'U':
+		size := 4
+		if c == 'U' {
+			size = 8
+		}
+		if p.pos+size > len(p.src) {
+			return p.errorf("invalid unicode escape")
+		}
+		code, err := strconv.ParseUint(p.src[p.pos:p.pos+size], 16, 32)
+		if err != nil {
+			return p.errorf("invalid unicode escape %q", p.src[p.pos:p.pos+size])
+		}
+		sb.WriteRune(rune(code))
+		p.pos += size
+	default:
+		return p.errorf("invalid escape sequence \\%c", c)
+	}
+	return nil
+}
+
+func (p *tomlParser) add(key, value string, line int) {
+	p.entries = append(p.entries, Match{LineNum: line, Key: key, Value: value})
+}
+
+func isTOMLBareKeyChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-'
+}
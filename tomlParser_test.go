@@ -0,0 +1,48 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestParseTomlFile(t *testing.T) {
+	path := abs("./testdata/unit/fixtures/unit.toml")
+	testCases := []testCaseParseFile{
+		{
+			filePath:      path,
+			searchPattern: "poetry",
+			expectedMatches: []Match{
+				{Path: path, LineNum: 3, Key: "tool.poetry.name", Value: "varip-example"},
+				{Path: path, LineNum: 4, Key: "tool.poetry.version", Value: "0.1.0"},
+				{Path: path, LineNum: 6, Key: "tool.poetry.authors.[0]", Value: "Jane Doe <jane@example.com>"},
+				{Path: path, LineNum: 7, Key: "tool.poetry.authors.[1]", Value: "John Doe <john@example.com>"},
+				{Path: path, LineNum: 11, Key: "tool.poetry.dependencies.python", Value: "^3.11"},
+				{Path: path, LineNum: 12, Key: "tool.poetry.dependencies.requests.version", Value: "2.31.0"},
+				{Path: path, LineNum: 12, Key: "tool.poetry.dependencies.requests.extras.[0]", Value: "socks"},
+			},
+		},
+		{
+			filePath:      path,
+			searchPattern: "database",
+			expectedMatches: []Match{
+				{Path: path, LineNum: 15, Key: "database.host", Value: "localhost"},
+				{Path: path, LineNum: 16, Key: "database.port", Value: "5432"},
+				{Path: path, LineNum: 17, Key: "database.connection.timeout", Value: "30"},
+				{Path: path, LineNum: 18, Key: "database.pool.max_size", Value: "10"},
+				{Path: path, LineNum: 19, Key: "database.password", Value: "s3cr3t"},
+			},
+		},
+		{
+			filePath:      path,
+			searchPattern: "servers",
+			expectedMatches: []Match{
+				{Path: path, LineNum: 23, Key: "servers.[0].name", Value: "alpha"},
+				{Path: path, LineNum: 24, Key: "servers.[0].ip", Value: "10.0.0.1"},
+				{Path: path, LineNum: 27, Key: "servers.[1].name", Value: "beta"},
+				{Path: path, LineNum: 28, Key: "servers.[1].ip", Value: "10.0.0.2"},
+				{Path: path, LineNum: 31, Key: "servers.[1].ports.[0].number", Value: "8080"},
+			},
+		},
+	}
+
+	assertParsedMatches(t, ParseTOMLFile, testCases)
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// usagePattern is a way source code reads an environment variable (or property), with the name in the first group.
+type usagePattern struct {
+	name string
+	re   *regexp.Regexp
+}
+
+var javaUsagePatterns = []usagePattern{
+	{"System.getenv", regexp.MustCompile(`System\.getenv\(\s*"([^"]+)"`)},
+	// Spring property placeholders, with an optional default and the \$ escape Kotlin strings need
+	{"@Value", regexp.MustCompile(`@Value\(\s*"\\?\$\{([^}:]+)[^}]*\}"`)},
+}
+
+var jsUsagePatterns = []usagePattern{
+	{"process.env", regexp.MustCompile(`process\.env\.([A-Za-z_$][\w$]*)`)},
+	{"process.env", regexp.MustCompile(`process\.env\[\s*["'` + "`" + `]([^"'` + "`" + `]+)["'` + "`" + `]\s*\]`)},
+	{"import.meta.env", regexp.MustCompile(`import\.meta\.env\.([A-Za-z_$][\w$]*)`)},
+}
+
+// usagePatterns maps source file extensions to the patterns used to find environment variable usages.
+var usagePatterns = map[string][]usagePattern{
+	".go": {
+		{"os.Getenv", regexp.MustCompile(`os\.Getenv\(\s*"([^"]+)"`)},
+		{"os.LookupEnv", regexp.MustCompile(`os\.LookupEnv\(\s*"([^"]+)"`)},
+	},
+	".java": javaUsagePatterns,
+	".kt":   javaUsagePatterns,
+	".kts":  javaUsagePatterns,
+	".js":   jsUsagePatterns,
+	".jsx":  jsUsagePatterns,
+	".mjs":  jsUsagePatterns,
+	".cjs":  jsUsagePatterns,
+	".ts":   jsUsagePatterns,
+	".tsx":  jsUsagePatterns,
+	".py": {
+		{"os.environ", regexp.MustCompile(`os\.environ\[\s*["']([^"']+)["']\s*\]`)},
+		{"os.environ", regexp.MustCompile(`os\.environ\.get\(\s*["']([^"']+)["']`)},
+		{"os.getenv", regexp.MustCompile(`os\.getenv\(\s*["']([^"']+)["']`)},
+	},
+	".rb": {
+		{"ENV", regexp.MustCompile(`\bENV\[\s*["']([^"']+)["']\s*\]`)},
+		{"ENV", regexp.MustCompile(`\bENV\.fetch\(\s*["']([^"']+)["']`)},
+	},
+}
+
+// ParseSourceFile scans a source file for the places environment variables are read, and returns all matches.
+// Matches are flagged as usages, with the variable name as the key, the way it is read as the scope and the source line as the value.
+// Parses .go, .java, .kt, .js, .ts, .py, .rb
+func ParseSourceFile(filePath string, re *regexp.Regexp) ([]Match, error) {
+	patterns, ok := usagePatterns[filepath.Ext(filePath)]
+	if !ok {
+		return nil, nil
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	// Minified sources can have very long lines
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	lineNum := 0
+
+	var matches []Match
+	for scanner.Scan() {
+		line := scanner.Text()
+		lineNum++
+
+		for _, pattern := range patterns {
+			for _, loc := range pattern.re.FindAllStringSubmatchIndex(line, -1) {
+				key := line[loc[2]:loc[3]]
+				if !re.MatchString(key) {
+					continue
+				}
+				matches = append(matches, Match{
+					Path:    filePath,
+					LineNum: lineNum,
+					Column:  loc[2] + 1,
+					Key:     key,
+					Value:   strings.TrimSpace(line),
+					Scope:   pattern.name,
+					Usage:   true,
+				})
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return matches, nil
+}
+
+// isSourceFile reports whether the given path is a source file that can be scanned for usages.
+func isSourceFile(path string) bool {
+	_, ok := usagePatterns[filepath.Ext(path)]
+	return ok
+}
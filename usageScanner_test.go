@@ -0,0 +1,67 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestParseSourceFile(t *testing.T) {
+	goFile := abs("./testdata/unit/fixtures/usages/main.go")
+	javaFile := abs("./testdata/unit/fixtures/usages/Config.java")
+	kotlinFile := abs("./testdata/unit/fixtures/usages/Config.kt")
+	tsFile := abs("./testdata/unit/fixtures/usages/db.ts")
+	pyFile := abs("./testdata/unit/fixtures/usages/settings.py")
+	rbFile := abs("./testdata/unit/fixtures/usages/config.rb")
+	testCases := []testCaseParseFile{
+		{
+			filePath:      goFile,
+			searchPattern: "db_",
+			expectedMatches: []Match{
+				{Path: goFile, LineNum: 6, Column: 21, Key: "DB_HOST", Value: `host := os.Getenv("DB_HOST")`, Scope: "os.Getenv", Usage: true},
+				{Path: goFile, LineNum: 7, Column: 28, Key: "DB_PORT", Value: `port, ok := os.LookupEnv("DB_PORT")`, Scope: "os.LookupEnv", Usage: true},
+			},
+		},
+		{
+			filePath:      javaFile,
+			searchPattern: "d",
+			expectedMatches: []Match{
+				{Path: javaFile, LineNum: 2, Column: 15, Key: "spring.datasource.url", Value: `@Value("${spring.datasource.url:jdbc:h2:mem}")`, Scope: "@Value", Usage: true},
+				{Path: javaFile, LineNum: 5, Column: 34, Key: "DB_HOST", Value: `String host = System.getenv("DB_HOST");`, Scope: "System.getenv", Usage: true},
+			},
+		},
+		{
+			filePath:      kotlinFile,
+			searchPattern: "datasource",
+			expectedMatches: []Match{
+				{Path: kotlinFile, LineNum: 1, Column: 25, Key: "spring.datasource.username", Value: `class Config(@Value("\${spring.datasource.username}") val username: String)`, Scope: "@Value", Usage: true},
+			},
+		},
+		{
+			filePath:      tsFile,
+			searchPattern: "db_",
+			expectedMatches: []Match{
+				{Path: tsFile, LineNum: 1, Column: 26, Key: "DB_HOST", Value: `const host = process.env.DB_HOST ?? process.env["DB_FALLBACK_HOST"];`, Scope: "process.env", Usage: true},
+				{Path: tsFile, LineNum: 1, Column: 50, Key: "DB_FALLBACK_HOST", Value: `const host = process.env.DB_HOST ?? process.env["DB_FALLBACK_HOST"];`, Scope: "process.env", Usage: true},
+				{Path: tsFile, LineNum: 2, Column: 30, Key: "VITE_DB_PORT", Value: `const port = import.meta.env.VITE_DB_PORT;`, Scope: "import.meta.env", Usage: true},
+			},
+		},
+		{
+			filePath:      pyFile,
+			searchPattern: "db_",
+			expectedMatches: []Match{
+				{Path: pyFile, LineNum: 3, Column: 20, Key: "DB_HOST", Value: `HOST = os.environ["DB_HOST"]`, Scope: "os.environ", Usage: true},
+				{Path: pyFile, LineNum: 4, Column: 24, Key: "DB_PORT", Value: `PORT = os.environ.get('DB_PORT', 5432)`, Scope: "os.environ", Usage: true},
+				{Path: pyFile, LineNum: 5, Column: 19, Key: "DB_NAME", Value: `NAME = os.getenv("DB_NAME")`, Scope: "os.getenv", Usage: true},
+			},
+		},
+		{
+			filePath:      rbFile,
+			searchPattern: "db_",
+			expectedMatches: []Match{
+				{Path: rbFile, LineNum: 1, Column: 13, Key: "DB_HOST", Value: `host = ENV['DB_HOST']`, Scope: "ENV", Usage: true},
+				{Path: rbFile, LineNum: 2, Column: 19, Key: "DB_PORT", Value: `port = ENV.fetch("DB_PORT", 5432)`, Scope: "ENV", Usage: true},
+			},
+		},
+	}
+
+	assertParsedMatches(t, ParseSourceFile, testCases)
+}
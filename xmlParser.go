@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/xml"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// xmlKeyAttributes and xmlValueAttribute describe the common key/value attribute conventions,
+// e.g. .NET <add key="DbConn" value="..."/> and Spring <property name="url" value="..."/>.
+var xmlKeyAttributes = []string{"key", "name"}
+
+const xmlValueAttribute = "value"
+
+type xmlElement struct {
+	path    string
+	lineNum int
+	text    strings.Builder
+}
+
+// ParseXMLFile parses an XML file, flattens element paths and attributes into keys, and finds matches based on the given regexp.
+// Elements following a key/value attribute convention are reported by their key attribute instead.
+// Parses .xml, .config
+func ParseXMLFile(filePath string, re *regexp.Regexp) ([]Match, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	decoder := xml.NewDecoder(file)
+	// Non UTF-8 documents are read as is, which is good enough for searching keys
+	decoder.CharsetReader = func(_ string, input io.Reader) (io.Reader, error) {
+		return input, nil
+	}
+
+	var matches []Match
+	add := func(lineNum int, key, value string) {
+		if re.MatchString(key) {
+			matches = append(matches, Match{Path: filePath, LineNum: lineNum, Key: key, Value: value})
+		}
+	}
+
+	var stack []*xmlElement
+	for {
+		// The position before reading a token is the start of that token
+		lineNum, _ := decoder.InputPos()
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			path := t.Name.Local
+			if len(stack) > 0 {
+				path = stack[len(stack)-1].path + "." + path
+			}
+			stack = append(stack, &xmlElement{path: path, lineNum: lineNum})
+
+			if key, value, ok := xmlKeyValue(t.Attr); ok {
+				add(lineNum, key, value)
+				continue
+			}
+			for _, attr := range t.Attr {
+				if attr.Name.Space == "xmlns" || attr.Name.Local == "xmlns" {
+					continue
+				}
+				add(lineNum, path+".@"+attr.Name.Local, attr.Value)
+			}
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text.Write(t)
+			}
+		case xml.EndElement:
+			element := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if text := strings.TrimSpace(element.text.String()); text != "" {
+				add(element.lineNum, element.path, text)
+			}
+		}
+	}
+
+	return matches, nil
+}
+
+// xmlKeyValue returns the key and value of an element using a key/value attribute convention.
+func xmlKeyValue(attrs []xml.Attr) (string, string, bool) {
+	var key, value string
+	var hasKey, hasValue bool
+	for _, attr := range attrs {
+		for _, keyAttr := range xmlKeyAttributes {
+			if attr.Name.Local == keyAttr && !hasKey {
+				key, hasKey = attr.Value, true
+			}
+		}
+		if attr.Name.Local == xmlValueAttribute {
+			value, hasValue = attr.Value, true
+		}
+	}
+	return key, value, hasKey && hasValue
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestParseXmlFile(t *testing.T) {
+	pom := abs("./testdata/unit/fixtures/unit.xml")
+	webConfig := abs("./testdata/unit/fixtures/unit.config")
+	testCases := []testCaseParseFile{
+		{
+			filePath:      pom,
+			searchPattern: "properties",
+			expectedMatches: []Match{
+				{Path: pom, LineNum: 6, Key: "project.properties.java.version", Value: "17"},
+				{Path: pom, LineNum: 7, Key: "project.properties.spring.datasource.url", Value: "jdbc:mysql://localhost/dev"},
+			},
+		},
+		{
+			filePath:      pom,
+			searchPattern: "name",
+			expectedMatches: []Match{
+				{Path: pom, LineNum: 11, Key: "username", Value: "${DB_USERNAME}"},
+			},
+		},
+		{
+			filePath:      pom,
+			searchPattern: "bean",
+			expectedMatches: []Match{
+				{Path: pom, LineNum: 10, Key: "project.beans.bean.@id", Value: "dataSource"},
+				{Path: pom, LineNum: 10, Key: "project.beans.bean.@class", Value: "org.apache.commons.dbcp.BasicDataSource"},
+			},
+		},
+		{
+			filePath:      webConfig,
+			searchPattern: "conn",
+			expectedMatches: []Match{
+				{Path: webConfig, LineNum: 4, Key: "DbConn", Value: "Server=localhost;Database=app"},
+				{Path: webConfig, LineNum: 9, Key: "configuration.connectionStrings.add.@name", Value: "Default"},
+				{Path: webConfig, LineNum: 9, Key: "configuration.connectionStrings.add.@connectionString", Value: "Server=db;Database=app"},
+			},
+		},
+		{
+			filePath:      webConfig,
+			searchPattern: "apikey",
+			expectedMatches: []Match{
+				{Path: webConfig, LineNum: 5, Key: "ApiKey", Value: "${API_KEY}"},
+			},
+		},
+	}
+
+	assertParsedMatches(t, ParseXMLFile, testCases)
+}